@@ -0,0 +1,97 @@
+package apptickets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"hash/crc32"
+
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ErrCRCMismatch is returned when checksum of decrypted app ticket is invalid.
+var ErrCRCMismatch = errors.New("encrypted ticket crc mismatch")
+
+// EncryptedTicket is a decrypted content of encrypted app ticket.
+type EncryptedTicket struct {
+	Version  uint32
+	UserData []byte
+	// Ownership is an ownership ticket of user. It isn't signed,
+	// because ticket is trusted after decryption, so Verify mustn't be called for it.
+	Ownership *OwnershipTicket
+}
+
+// DecryptEncryptedAppTicket decrypt serialized encrypted app ticket
+// with the symmetric key of app, which is issued on Steamworks partner site.
+func DecryptEncryptedAppTicket(data []byte, key []byte) (*EncryptedTicket, error) {
+	var msg protobuf.EncryptedAppTicket
+
+	err := proto.Unmarshal(data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal encrypted app ticket")
+	}
+
+	encrypted := msg.GetEncryptedTicket()
+	if len(encrypted) < 2*aes.BlockSize || len(encrypted)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted ticket size")
+	}
+
+	decrypted, err := decrypt(key, encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checksum is calculated over decrypted ticket without padding.
+	if crc32.ChecksumIEEE(decrypted) != msg.GetCrcEncryptedticket() {
+		return nil, ErrCRCMismatch
+	}
+
+	userDataSize := int(msg.GetCbEncrypteduserdata())
+	if len(decrypted) < userDataSize+4 {
+		return nil, errors.New("decrypted ticket is too short")
+	}
+
+	ownership, err := ParseOwnershipTicket(decrypted[userDataSize:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse ownership ticket")
+	}
+
+	// Ownership ticket isn't signed in encrypted ticket, so trailing bytes aren't a signature.
+	ownership.Signature = nil
+
+	return &EncryptedTicket{
+		Version:   msg.GetTicketVersionNo(),
+		UserData:  decrypted[:userDataSize],
+		Ownership: ownership,
+	}, nil
+}
+
+// decrypt performs a decryption using AES/CBC/PKCS7 with an IV
+// prepended using AES/ECB/None, like crypto.Aes does.
+// Padding is checked, because key mismatch results in garbage padding.
+func decrypt(key, src []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	block.Decrypt(iv, src[:aes.BlockSize])
+
+	data := make([]byte, len(src)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, src[aes.BlockSize:])
+
+	padLen := int(data[len(data)-1])
+	if padLen == 0 || padLen > aes.BlockSize {
+		return nil, errors.New("invalid ticket padding, key may be wrong")
+	}
+
+	for _, b := range data[len(data)-padLen:] {
+		if int(b) != padLen {
+			return nil, errors.New("invalid ticket padding, key may be wrong")
+		}
+	}
+
+	return data[:len(data)-padLen], nil
+}
@@ -0,0 +1,90 @@
+// Package apptickets used to request app ownership tickets and encrypted app tickets.
+//
+// App ownership ticket is signed by Steam with the universe private RSA key,
+// and proves, that user owns app and its DLC.
+// Encrypted app ticket is encrypted with the symmetric key of app,
+// so only developer of app is able to decrypt it on backend.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package apptickets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Module used to request app tickets.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of apptickets Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// GetAppOwnershipTicket request signed ownership ticket of app for current user.
+// Raw ticket is returned, ParseOwnershipTicket used to decode it.
+func (m *Module) GetAppOwnershipTicket(ctx context.Context, appID uint32) ([]byte, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientGetAppOwnershipTicket, &protobuf.CMsgClientGetAppOwnershipTicket{
+		AppId: proto.Uint32(appID),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request app ownership ticket")
+	}
+
+	var msg protobuf.CMsgClientGetAppOwnershipTicketResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read app ownership ticket")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("app ownership ticket request failed with result %s", result.String())
+	}
+
+	return msg.GetTicket(), nil
+}
+
+// RequestEncryptedAppTicket request encrypted app ticket, which contains userData.
+// Ticket is serialized, so it can be sent to backend of app as is,
+// and decrypted there with DecryptEncryptedAppTicket.
+func (m *Module) RequestEncryptedAppTicket(ctx context.Context, appID uint32, userData []byte) ([]byte, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientRequestEncryptedAppTicket, &protobuf.CMsgClientRequestEncryptedAppTicket{
+		AppId:    proto.Uint32(appID),
+		Userdata: userData,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request encrypted app ticket")
+	}
+
+	var msg protobuf.CMsgClientRequestEncryptedAppTicketResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read encrypted app ticket")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("encrypted app ticket request failed with result %s", result.String())
+	}
+
+	ticket, err := proto.Marshal(msg.GetEncryptedAppTicket())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal encrypted app ticket")
+	}
+
+	return ticket, nil
+}
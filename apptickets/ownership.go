@@ -0,0 +1,199 @@
+package apptickets
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	steamcrypto "github.com/furdarius/steamprotocol/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// signatureSize is a size of RSA signature, which follows signed part of ownership ticket.
+	signatureSize = 128
+
+	// headerSize is a size of length and fixed fields, which start signed part of ownership ticket.
+	headerSize = 40
+)
+
+// DLC is a DLC of app, which is owned by user.
+type DLC struct {
+	AppID    uint32
+	Licenses []uint32
+}
+
+// OwnershipTicket proves, that user owns app.
+//
+// Binary layout, all integers are little endian:
+//
+//	uint32 length of signed part
+//	uint32 version
+//	uint64 steamid
+//	uint32 appid
+//	uint32 external ip
+//	uint32 internal ip
+//	uint32 ownership flags
+//	uint32 generated time
+//	uint32 expiration time
+//	uint16 licenses count, uint32 package id of each license
+//	uint16 dlc count, for each dlc: uint32 appid, uint16 licenses count, uint32 licenses
+//	uint16 reserved
+//	128 bytes RSA signature of signed part
+type OwnershipTicket struct {
+	Version    uint32
+	SteamID    uint64
+	AppID      uint32
+	ExternalIP net.IP
+	InternalIP net.IP
+	Flags      uint32
+	Generated  time.Time
+	Expires    time.Time
+	Licenses   []uint32
+	DLC        []DLC
+
+	// Signed is a part of ticket covered by Signature.
+	Signed    []byte
+	Signature []byte
+}
+
+// ParseOwnershipTicket decode binary ownership ticket.
+// Signature isn't checked, so Verify must be used for untrusted tickets.
+func ParseOwnershipTicket(data []byte) (*OwnershipTicket, error) {
+	if len(data) < 4 {
+		return nil, errors.New("ownership ticket is too short")
+	}
+
+	length := binary.LittleEndian.Uint32(data)
+	if length < headerSize || uint64(length) > uint64(len(data)) {
+		return nil, errors.New("invalid ownership ticket length")
+	}
+
+	r := bytes.NewReader(data[4:length])
+
+	var header struct {
+		Version    uint32
+		SteamID    uint64
+		AppID      uint32
+		ExternalIP uint32
+		InternalIP uint32
+		Flags      uint32
+		Generated  uint32
+		Expires    uint32
+	}
+
+	err := binary.Read(r, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read ticket header")
+	}
+
+	ticket := &OwnershipTicket{
+		Version:    header.Version,
+		SteamID:    header.SteamID,
+		AppID:      header.AppID,
+		ExternalIP: steamprotocol.IPv4(header.ExternalIP),
+		InternalIP: steamprotocol.IPv4(header.InternalIP),
+		Flags:      header.Flags,
+		Generated:  time.Unix(int64(header.Generated), 0),
+		Expires:    time.Unix(int64(header.Expires), 0),
+		Signed:     data[:length],
+	}
+
+	ticket.Licenses, err = readLicenses(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read licenses")
+	}
+
+	var dlcCount uint16
+
+	err = binary.Read(r, binary.LittleEndian, &dlcCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read dlc count")
+	}
+
+	for i := 0; i < int(dlcCount); i++ {
+		var dlc DLC
+
+		err = binary.Read(r, binary.LittleEndian, &dlc.AppID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read dlc appid")
+		}
+
+		dlc.Licenses, err = readLicenses(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read dlc licenses")
+		}
+
+		ticket.DLC = append(ticket.DLC, dlc)
+	}
+
+	if len(data) >= int(length)+signatureSize {
+		ticket.Signature = data[length : int(length)+signatureSize]
+	}
+
+	return ticket, nil
+}
+
+// Verify check signature of ticket with the universe public key.
+func (t *OwnershipTicket) Verify(universe steamprotocol.EUniverse) error {
+	if len(t.Signature) == 0 {
+		return errors.New("ticket is not signed")
+	}
+
+	key, err := steamcrypto.PublicKey(universe)
+	if err != nil {
+		return err
+	}
+
+	hash := sha1.Sum(t.Signed)
+
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA1, hash[:], t.Signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid ticket signature")
+	}
+
+	return nil
+}
+
+// IsExpired reports whether ticket is expired.
+func (t *OwnershipTicket) IsExpired() bool {
+	return time.Now().After(t.Expires)
+}
+
+// OwnsApp reports whether ticket grants app or its DLC.
+func (t *OwnershipTicket) OwnsApp(appID uint32) bool {
+	if t.AppID == appID {
+		return true
+	}
+
+	for _, dlc := range t.DLC {
+		if dlc.AppID == appID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readLicenses(r *bytes.Reader) ([]uint32, error) {
+	var count uint16
+
+	err := binary.Read(r, binary.LittleEndian, &count)
+	if err != nil {
+		return nil, err
+	}
+
+	licenses := make([]uint32, count)
+
+	err = binary.Read(r, binary.LittleEndian, licenses)
+	if err != nil {
+		return nil, err
+	}
+
+	return licenses, nil
+}
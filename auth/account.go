@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"bytes"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Wallet contains Steam wallet balance of account.
+// Balances are in cents of Currency.
+type Wallet struct {
+	HasWallet      bool
+	Balance        int32
+	BalanceDelayed int32
+	Currency       steamprotocol.ECurrencyCode
+}
+
+// Account contains account information, sent by Steam after logon.
+type Account struct {
+	PersonaName string
+	Country     string
+	Flags       steamprotocol.EAccountFlags
+
+	Email          string
+	EmailValidated bool
+
+	// VACBannedApps is list of app ids, where account is VAC banned.
+	VACBannedApps []uint32
+
+	Limited                      bool
+	CommunityBanned              bool
+	Locked                       bool
+	LimitedAllowedToInviteFriend bool
+
+	Wallet Wallet
+}
+
+// IsVACBanned reports whether account has VAC ban in any app.
+func (a Account) IsVACBanned() bool {
+	return len(a.VACBannedApps) > 0
+}
+
+// Account return copy of account information received since logon.
+func (m *Module) Account() Account {
+	m.accountMu.RLock()
+	defer m.accountMu.RUnlock()
+
+	acc := m.account
+	acc.VACBannedApps = append([]uint32(nil), m.account.VACBannedApps...)
+
+	return acc
+}
+
+func (m *Module) handleAccountInfo(p *steamprotocol.Packet) error {
+	var (
+		header *messages.HeaderProto = messages.NewHeaderProto(steamprotocol.EMsg_Invalid)
+		msg    protobuf.CMsgClientAccountInfo
+	)
+
+	dataBuf := bytes.NewBuffer(p.Data)
+
+	err := header.Deserialize(dataBuf)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize account info header")
+	}
+
+	err = proto.Unmarshal(dataBuf.Bytes(), &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal account info msg")
+	}
+
+	event := AccountInfoEvent{
+		PersonaName: msg.GetPersonaName(),
+		Country:     msg.GetIpCountry(),
+		Flags:       steamprotocol.EAccountFlags(msg.GetAccountFlags()),
+	}
+
+	m.accountMu.Lock()
+	m.account.PersonaName = event.PersonaName
+	m.account.Country = event.Country
+	m.account.Flags = event.Flags
+	m.accountMu.Unlock()
+
+	return m.eventManager.FireEvent(event)
+}
+
+func (m *Module) handleEmailAddrInfo(p *steamprotocol.Packet) error {
+	var (
+		header *messages.HeaderProto = messages.NewHeaderProto(steamprotocol.EMsg_Invalid)
+		msg    protobuf.CMsgClientEmailAddrInfo
+	)
+
+	dataBuf := bytes.NewBuffer(p.Data)
+
+	err := header.Deserialize(dataBuf)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize email addr info header")
+	}
+
+	err = proto.Unmarshal(dataBuf.Bytes(), &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal email addr info msg")
+	}
+
+	event := EmailAddrInfoEvent{
+		Email:     msg.GetEmailAddress(),
+		Validated: msg.GetEmailIsValidated(),
+	}
+
+	m.accountMu.Lock()
+	m.account.Email = event.Email
+	m.account.EmailValidated = event.Validated
+	m.accountMu.Unlock()
+
+	return m.eventManager.FireEvent(event)
+}
+
+func (m *Module) handleVACBanStatus(p *steamprotocol.Packet) error {
+	var (
+		header messages.ExtendedHeader
+		msg    messages.ClientVACBanStatus
+	)
+
+	r := bytes.NewReader(p.Data)
+
+	err := header.Deserialize(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize vac ban status header")
+	}
+
+	err = msg.Deserialize(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize vac ban status msg")
+	}
+
+	m.accountMu.Lock()
+	m.account.VACBannedApps = msg.BannedApps
+	m.accountMu.Unlock()
+
+	return m.eventManager.FireEvent(VACStatusEvent{
+		BannedApps: append([]uint32(nil), msg.BannedApps...),
+	})
+}
+
+func (m *Module) handleIsLimitedAccount(p *steamprotocol.Packet) error {
+	var (
+		header *messages.HeaderProto = messages.NewHeaderProto(steamprotocol.EMsg_Invalid)
+		msg    protobuf.CMsgClientIsLimitedAccount
+	)
+
+	dataBuf := bytes.NewBuffer(p.Data)
+
+	err := header.Deserialize(dataBuf)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize is limited account header")
+	}
+
+	err = proto.Unmarshal(dataBuf.Bytes(), &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal is limited account msg")
+	}
+
+	event := LimitedAccountEvent{
+		Limited:                      msg.GetBisLimitedAccount(),
+		CommunityBanned:              msg.GetBisCommunityBanned(),
+		Locked:                       msg.GetBisLockedAccount(),
+		LimitedAllowedToInviteFriend: msg.GetBisLimitedAccountAllowedToInviteFriends(),
+	}
+
+	m.accountMu.Lock()
+	m.account.Limited = event.Limited
+	m.account.CommunityBanned = event.CommunityBanned
+	m.account.Locked = event.Locked
+	m.account.LimitedAllowedToInviteFriend = event.LimitedAllowedToInviteFriend
+	m.accountMu.Unlock()
+
+	return m.eventManager.FireEvent(event)
+}
+
+func (m *Module) handleWalletInfoUpdate(p *steamprotocol.Packet) error {
+	var (
+		header *messages.HeaderProto = messages.NewHeaderProto(steamprotocol.EMsg_Invalid)
+		msg    protobuf.CMsgClientWalletInfoUpdate
+	)
+
+	dataBuf := bytes.NewBuffer(p.Data)
+
+	err := header.Deserialize(dataBuf)
+	if err != nil {
+		return errors.Wrap(err, "failed to deserialize wallet info update header")
+	}
+
+	err = proto.Unmarshal(dataBuf.Bytes(), &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal wallet info update msg")
+	}
+
+	wallet := Wallet{
+		HasWallet:      msg.GetHasWallet(),
+		Balance:        msg.GetBalance(),
+		BalanceDelayed: msg.GetBalanceDelayed(),
+		Currency:       steamprotocol.ECurrencyCode(msg.GetCurrency()),
+	}
+
+	m.accountMu.Lock()
+	m.account.Wallet = wallet
+	m.accountMu.Unlock()
+
+	return m.eventManager.FireEvent(WalletInfoEvent{
+		Wallet: wallet,
+	})
+}
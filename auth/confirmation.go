@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+)
+
+// Confirmation tags are used to generate confirmation keys for different mobileconf operations.
+const (
+	ConfirmationTagList    = "conf"
+	ConfirmationTagDetails = "details"
+	ConfirmationTagAllow   = "allow"
+	ConfirmationTagCancel  = "cancel"
+)
+
+// maxConfirmationTagLen is the max count of tag bytes used in HMAC.
+const maxConfirmationTagLen = 32
+
+// GenerateConfirmationKey generate mobile confirmation key from identity secret.
+// Key is base64 encoded HMAC-SHA1 of big endian timestamp followed by tag.
+func GenerateConfirmationKey(identitySecret string, timestamp int64, tag string) (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(identitySecret)
+	if err != nil {
+		return "", err
+	}
+
+	if len(tag) > maxConfirmationTagLen {
+		tag = tag[:maxConfirmationTagLen]
+	}
+
+	buf := make([]byte, 8, 8+len(tag))
+	binary.BigEndian.PutUint64(buf, uint64(timestamp))
+	buf = append(buf, tag...)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(buf)
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// DeviceID generate android device id for account.
+// It's the same value as mobile authenticator generates: SHA1 hex of SteamID formatted as GUID.
+func DeviceID(steamID uint64) string {
+	sum := sha1.Sum([]byte(strconv.FormatUint(steamID, 10)))
+	h := hex.EncodeToString(sum[:])
+
+	return "android:" + h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
@@ -11,6 +11,9 @@ type SuccessfullyAuthenticatedEvent struct {
 	Heartbeat time.Duration
 	SteamID   uint64
 	SessionID int32
+	CellID    uint32
+	// WebAPINonce is used to authenticate web session once.
+	WebAPINonce string
 }
 
 // AuthenticationFailedEvent is fired when failed CMsgClientLogonResponse is received.
@@ -33,3 +36,34 @@ type NewLoginKeyAcceptedEvent struct {
 type MachineAuthUpdateEvent struct {
 	Hash []byte
 }
+
+// AccountInfoEvent is fired when CMsgClientAccountInfo is received.
+type AccountInfoEvent struct {
+	PersonaName string
+	Country     string
+	Flags       steamprotocol.EAccountFlags
+}
+
+// EmailAddrInfoEvent is fired when CMsgClientEmailAddrInfo is received.
+type EmailAddrInfoEvent struct {
+	Email     string
+	Validated bool
+}
+
+// VACStatusEvent is fired when ClientVACBanStatus is received.
+type VACStatusEvent struct {
+	BannedApps []uint32
+}
+
+// LimitedAccountEvent is fired when CMsgClientIsLimitedAccount is received.
+type LimitedAccountEvent struct {
+	Limited                      bool
+	CommunityBanned              bool
+	Locked                       bool
+	LimitedAllowedToInviteFriend bool
+}
+
+// WalletInfoEvent is fired when CMsgClientWalletInfoUpdate is received.
+type WalletInfoEvent struct {
+	Wallet Wallet
+}
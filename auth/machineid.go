@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+
+	"github.com/furdarius/steamprotocol/keyvalues"
+)
+
+// GenerateMachineID build value of CMsgClientLogon.MachineId from seed.
+//
+// Steam client sends binary KeyValues object "MessageObject" with SHA1 hex
+// hashes of machine GUID (BB3), MAC address (FF2) and disk ID (3B3).
+// Steam uses it to recognize device, so the same seed must be used
+// for every logon to look like the same machine.
+func GenerateMachineID(seed string) ([]byte, error) {
+	kv := keyvalues.NewObject("MessageObject",
+		keyvalues.NewString("BB3", machineIDHash(seed, "BB3")),
+		keyvalues.NewString("FF2", machineIDHash(seed, "FF2")),
+		keyvalues.NewString("3B3", machineIDHash(seed, "3B3")),
+	)
+
+	buf := new(bytes.Buffer)
+
+	err := kv.WriteBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func machineIDHash(seed string, key string) string {
+	sum := sha1.Sum([]byte(seed + "-" + key))
+
+	return hex.EncodeToString(sum[:])
+}
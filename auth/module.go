@@ -2,10 +2,11 @@ package auth
 
 import (
 	"bytes"
-
-	"time"
-
 	"crypto/sha1"
+	"fmt"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/furdarius/steamprotocol"
 	"github.com/furdarius/steamprotocol/crypto"
@@ -15,20 +16,47 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Log on with the given details. You must always specify username and
-// password. For the first login, don't set an authcode or a hash and you'll receive an error
-// and Steam will send you an authcode. Then you have to login again, this time with the authcode.
+// Log on with the given details. Details required depend on logon type.
+//
+// LogonUser requires username and password. For the first login, don't set an authcode
+// or a hash and you'll receive an error and Steam will send you an authcode.
+// Then you have to login again, this time with the authcode.
 // Shortly after logging in, you'll receive a MachineAuthUpdateEvent with a hash which allows
 // you to login without using an authcode in the future.
+// If you don't use Steam Guard, username and password are enough.
+//
+// LogonAnonUser doesn't require any details, and can be used to access PICS and content.
+//
+// LogonGameServer requires GameServerToken generated for GameServerAppID,
+// and LogonAnonGameServer requires only GameServerAppID.
 //
-// If you don't use Steam Guard, username and password are enough
+// User logons send machine ID generated from MachineIDSeed. If seed is empty,
+// username is used, or hostname for anonymous logon.
+
+// LogonType is used to select the way client logs on.
+type LogonType int
+
+const (
+	// LogonUser is logon of individual account with username and password.
+	LogonUser LogonType = iota
+	// LogonAnonUser is anonymous user logon.
+	LogonAnonUser
+	// LogonGameServer is game server logon with GameServerToken.
+	LogonGameServer
+	// LogonAnonGameServer is anonymous game server logon.
+	LogonAnonGameServer
+)
 
 // Details used to auth user
 type Details struct {
-	Username     string
-	Password     string
-	AuthCode     string
-	SharedSecret string
+	Type            LogonType
+	Username        string
+	Password        string
+	AuthCode        string
+	SharedSecret    string
+	GameServerToken string
+	GameServerAppID uint32
+	MachineIDSeed   string
 }
 
 // Module used to auth user.
@@ -63,8 +91,8 @@ type Module struct {
 	gen          *TOTPGenerator
 	details      Details
 	sessionKey   []byte
-	steamID      uint64
-	sessionID    int32
+	accountMu    sync.RWMutex
+	account      Account
 }
 
 // NewModule initialize new instance of auth Module.
@@ -108,38 +136,100 @@ func (m *Module) handlePacket(p *steamprotocol.Packet) error {
 	case steamprotocol.EMsg_ClientUpdateMachineAuth:
 		return m.handleUpdateMachineAuth(p)
 	case steamprotocol.EMsg_ClientAccountInfo:
-		// TODO: return m.handleAccountInfo(cl, p)
+		return m.handleAccountInfo(p)
+	case steamprotocol.EMsg_ClientEmailAddrInfo:
+		return m.handleEmailAddrInfo(p)
+	case steamprotocol.EMsg_ClientVACBanStatus:
+		return m.handleVACBanStatus(p)
+	case steamprotocol.EMsg_ClientIsLimitedAccount:
+		return m.handleIsLimitedAccount(p)
+	case steamprotocol.EMsg_ClientWalletInfoUpdate:
+		return m.handleWalletInfoUpdate(p)
 	}
 
 	return nil
 }
 
 func (m *Module) handleChannelEncryptedEvent() error {
-	if len(m.details.Username) == 0 {
-		return errors.New("empty username")
+	var (
+		eMsg        steamprotocol.EMsg
+		accountType steamprotocol.EAccountType
+		instance    uint32
+		responseMsg *protobuf.CMsgClientLogon
+		err         error
+	)
+
+	switch m.details.Type {
+	case LogonUser:
+		eMsg, accountType, instance = steamprotocol.EMsg_ClientLogon, steamprotocol.EAccountType_Individual, 1
+		responseMsg, err = m.userLogonMsg()
+	case LogonAnonUser:
+		eMsg, accountType = steamprotocol.EMsg_ClientLogon, steamprotocol.EAccountType_AnonUser
+		responseMsg, err = m.anonUserLogonMsg()
+	case LogonGameServer:
+		eMsg, accountType = steamprotocol.EMsg_ClientLogonGameServer, steamprotocol.EAccountType_GameServer
+		responseMsg, err = m.gameServerLogonMsg()
+	case LogonAnonGameServer:
+		eMsg, accountType = steamprotocol.EMsg_ClientLogonGameServer, steamprotocol.EAccountType_AnonGameServer
+		responseMsg, err = m.anonGameServerLogonMsg()
+	default:
+		return fmt.Errorf("unknown logon type %d", m.details.Type)
 	}
 
-	if len(m.details.Password) == 0 {
-		return errors.New("empty password")
+	if err != nil {
+		return err
 	}
 
-	responseHeader := messages.NewHeaderProto(steamprotocol.EMsg_ClientLogon)
+	responseHeader := messages.NewHeaderProto(eMsg)
 
 	steamID := steamprotocol.NewIdAdv(
 		0,
-		1,
+		instance,
 		int32(steamprotocol.EUniverse_Public),
-		int32(steamprotocol.EAccountType_Individual),
+		int32(accountType),
 	)
 
-	// Save for sending with SuccessfullyAuthenticatedEvent event
-	m.steamID = uint64(steamID)
-	m.sessionID = 0
+	// Session isn't assigned yet, so header must be filled manually
+	m.cl.ResetSession()
+
+	responseHeader.Data.Steamid = proto.Uint64(uint64(steamID))
+	responseHeader.Data.ClientSessionid = proto.Int32(0)
+
+	buf := new(bytes.Buffer)
+
+	err = responseHeader.Serialize(buf)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize header")
+	}
+
+	body, err := proto.Marshal(responseMsg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal response msg")
+	}
+
+	_, err = buf.Write(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to append msg to buffer")
+	}
+
+	err = m.cl.Write(buf.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "failed to write data")
+	}
+
+	return nil
+}
+
+func (m *Module) userLogonMsg() (*protobuf.CMsgClientLogon, error) {
+	if len(m.details.Username) == 0 {
+		return nil, errors.New("empty username")
+	}
 
-	responseHeader.Data.Steamid = proto.Uint64(m.steamID)
-	responseHeader.Data.ClientSessionid = proto.Int32(m.sessionID)
+	if len(m.details.Password) == 0 {
+		return nil, errors.New("empty password")
+	}
 
-	responseMsg := &protobuf.CMsgClientLogon{
+	msg := &protobuf.CMsgClientLogon{
 		AccountName:     &m.details.Username,
 		Password:        &m.details.Password,
 		ClientLanguage:  proto.String("english"),
@@ -148,41 +238,83 @@ func (m *Module) handleChannelEncryptedEvent() error {
 	}
 
 	if m.details.AuthCode != "" {
-		responseMsg.AuthCode = proto.String(m.details.AuthCode)
+		msg.AuthCode = proto.String(m.details.AuthCode)
 	}
 
 	if m.details.SharedSecret != "" {
 		code, err := m.gen.TwoFactorSynced(m.details.SharedSecret)
 		if err != nil {
-			return errors.Wrap(err, "failed to fetch two factor code")
+			return nil, errors.Wrap(err, "failed to fetch two factor code")
 		}
 
-		responseMsg.TwoFactorCode = proto.String(code)
+		msg.TwoFactorCode = proto.String(code)
 	}
 
-	buf := new(bytes.Buffer)
-
-	err := responseHeader.Serialize(buf)
+	machineID, err := m.machineID(m.details.Username)
 	if err != nil {
-		return errors.Wrap(err, "failed to serialize header")
+		return nil, err
 	}
 
-	body, err := proto.Marshal(responseMsg)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal response msg")
+	msg.MachineId = machineID
+
+	return msg, nil
+}
+
+func (m *Module) anonUserLogonMsg() (*protobuf.CMsgClientLogon, error) {
+	var hostname string
+
+	if m.details.MachineIDSeed == "" {
+		var err error
+
+		hostname, err = os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get hostname")
+		}
 	}
 
-	_, err = buf.Write(body)
+	machineID, err := m.machineID(hostname)
 	if err != nil {
-		return errors.Wrap(err, "failed to append msg to buffer")
+		return nil, err
 	}
 
-	err = m.cl.Write(buf.Bytes())
+	return &protobuf.CMsgClientLogon{
+		ClientLanguage:  proto.String("english"),
+		ProtocolVersion: proto.Uint32(messages.ClientLogonCurrentProtocol),
+		MachineId:       machineID,
+	}, nil
+}
+
+func (m *Module) machineID(defaultSeed string) ([]byte, error) {
+	seed := m.details.MachineIDSeed
+	if seed == "" {
+		seed = defaultSeed
+	}
+
+	machineID, err := GenerateMachineID(seed)
 	if err != nil {
-		return errors.Wrap(err, "failed to write data")
+		return nil, errors.Wrap(err, "failed to generate machine id")
 	}
 
-	return nil
+	return machineID, nil
+}
+
+func (m *Module) gameServerLogonMsg() (*protobuf.CMsgClientLogon, error) {
+	if len(m.details.GameServerToken) == 0 {
+		return nil, errors.New("empty game server token")
+	}
+
+	return &protobuf.CMsgClientLogon{
+		ProtocolVersion: proto.Uint32(messages.ClientLogonCurrentProtocol),
+		GameServerToken: proto.String(m.details.GameServerToken),
+		GameServerAppId: proto.Int32(int32(m.details.GameServerAppID)),
+	}, nil
+}
+
+func (m *Module) anonGameServerLogonMsg() (*protobuf.CMsgClientLogon, error) {
+	return &protobuf.CMsgClientLogon{
+		ProtocolVersion: proto.Uint32(messages.ClientLogonCurrentProtocol),
+		GameServerAppId: proto.Int32(int32(m.details.GameServerAppID)),
+	}, nil
 }
 
 func (m *Module) handleLogOnResponse(p *steamprotocol.Packet) error {
@@ -206,10 +338,26 @@ func (m *Module) handleLogOnResponse(p *steamprotocol.Packet) error {
 	result := steamprotocol.EResult(msg.GetEresult())
 
 	if result == steamprotocol.EResult_OK {
+		session := steamprotocol.Session{
+			SteamID:   header.Data.GetSteamid(),
+			SessionID: header.Data.GetClientSessionid(),
+			CellID:    msg.GetCellId(),
+		}
+
+		m.cl.SetSession(session)
+
+		// Account info is sent again after every logon
+		m.accountMu.Lock()
+		m.account = Account{}
+		m.accountMu.Unlock()
+
 		return m.eventManager.FireEvent(SuccessfullyAuthenticatedEvent{
 			Heartbeat: time.Duration(msg.GetOutOfGameHeartbeatSeconds()) * time.Second,
-			SteamID:   m.steamID,
-			SessionID: m.sessionID,
+			SteamID:   session.SteamID,
+			SessionID: session.SessionID,
+			CellID:    session.CellID,
+
+			WebAPINonce: msg.GetWebapiAuthenticateUserNonce(),
 		})
 	}
 
@@ -238,6 +386,8 @@ func (m *Module) handleLoggedOff(p *steamprotocol.Packet) error {
 
 	result := steamprotocol.EResult(msg.GetEresult())
 
+	m.cl.ResetSession()
+
 	return m.eventManager.FireEvent(LoggedOffEvent{
 		Result: result,
 	})
@@ -264,34 +414,13 @@ func (m *Module) handleNewLoginKey(p *steamprotocol.Packet) error {
 	uniqID := msg.GetUniqueId()
 	key := msg.GetLoginKey()
 
-	responseHeader := messages.NewHeaderProto(steamprotocol.EMsg_ClientNewLoginKeyAccepted)
-	responseHeader.Data.Steamid = proto.Uint64(m.steamID)
-	responseHeader.Data.ClientSessionid = proto.Int32(m.sessionID)
-
 	responseMsg := &protobuf.CMsgClientNewLoginKeyAccepted{
 		UniqueId: proto.Uint32(uniqID),
 	}
 
-	buf := new(bytes.Buffer)
-
-	err = responseHeader.Serialize(buf)
-	if err != nil {
-		return errors.Wrap(err, "failed to serialize header")
-	}
-
-	body, err := proto.Marshal(responseMsg)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal response msg")
-	}
-
-	_, err = buf.Write(body)
+	err = m.cl.Send(steamprotocol.EMsg_ClientNewLoginKeyAccepted, responseMsg)
 	if err != nil {
-		return errors.Wrap(err, "failed to append msg to buffer")
-	}
-
-	err = m.cl.Write(buf.Bytes())
-	if err != nil {
-		return errors.Wrap(err, "failed to write data")
+		return errors.Wrap(err, "failed to send new login key accepted msg")
 	}
 
 	return m.eventManager.FireEvent(NewLoginKeyAcceptedEvent{
@@ -323,8 +452,6 @@ func (m *Module) handleUpdateMachineAuth(p *steamprotocol.Packet) error {
 	shaHash := hash.Sum(nil)
 
 	responseHeader := messages.NewHeaderProto(steamprotocol.EMsg_ClientNewLoginKeyAccepted)
-	responseHeader.Data.Steamid = proto.Uint64(m.steamID)
-	responseHeader.Data.ClientSessionid = proto.Int32(m.sessionID)
 	responseHeader.Data.JobidTarget = header.Data.JobidSource
 
 	responseMsg := &protobuf.CMsgClientUpdateMachineAuthResponse{
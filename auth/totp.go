@@ -7,6 +7,8 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -14,6 +16,19 @@ import (
 const (
 	chars    = "23456789BCDFGHJKMNPQRTVWXY"
 	charsLen = uint32(len(chars))
+
+	// DefaultQueryTimeURL is url of ITwoFactorService/QueryTime WebAPI method.
+	DefaultQueryTimeURL = "https://api.steampowered.com/ITwoFactorService/QueryTime/v1/"
+
+	// TwoFactorPeriod is time window of one two-factor code.
+	TwoFactorPeriod = 30 * time.Second
+
+	// defaultProbeFrequency is used, when time tip wasn't received.
+	defaultProbeFrequency = time.Hour
+
+	// defaultTryAgain is delay before next time query, when previous one failed
+	// and time tip doesn't contain try again delay.
+	defaultTryAgain = time.Minute
 )
 
 type TimeTip struct {
@@ -30,23 +45,122 @@ type TimeTip struct {
 
 // TOTPGenerator is used to generate two factor auth code synced by time with Steam.
 // TOTP = Time-based One-time Password Algorithm
+//
+// Offset between local and Steam time is cached, and Steam is queried again
+// after probe frequency from time tip is elapsed. If Steam can't be queried,
+// last known offset is used, or local time if time was never synced.
 type TOTPGenerator struct {
-	cl *http.Client
+	cl           *http.Client
+	queryTimeURL string
+	now          func() time.Time
+	mu           sync.Mutex
+	offset       time.Duration
+	nextSync     time.Time
+	tryAgain     time.Duration
+	syncing      bool
 }
 
 // NewTOTPGenerator initialize new instance of TOTPGenerator
 func NewTOTPGenerator(cl *http.Client) *TOTPGenerator {
-	return &TOTPGenerator{cl}
+	return &TOTPGenerator{
+		cl:           cl,
+		queryTimeURL: DefaultQueryTimeURL,
+		now:          time.Now,
+	}
 }
 
-// TwoFactorSynced fetch time from Steam and return generated two-factor code synced by time with Steam
-func (gen *TOTPGenerator) TwoFactorSynced(sharedSecret string) (string, error) {
+// SetClock change function used to get local time.
+func (gen *TOTPGenerator) SetClock(now func() time.Time) {
+	gen.mu.Lock()
+	gen.now = now
+	gen.nextSync = time.Time{}
+	gen.mu.Unlock()
+}
+
+// SetQueryTimeURL change url of ITwoFactorService/QueryTime WebAPI method.
+func (gen *TOTPGenerator) SetQueryTimeURL(url string) {
+	gen.mu.Lock()
+	gen.queryTimeURL = url
+	gen.nextSync = time.Time{}
+	gen.mu.Unlock()
+}
+
+// Now return local time corrected by cached offset from Steam time.
+// Time is synced with Steam, if probe frequency is elapsed since last sync.
+// Steam is queried without lock held, so concurrent calls aren't blocked by it,
+// and use last known offset until query is done.
+func (gen *TOTPGenerator) Now() time.Time {
+	gen.mu.Lock()
+	now := gen.now()
+	needSync := !gen.syncing && !now.Before(gen.nextSync)
+	if needSync {
+		gen.syncing = true
+	}
+	gen.mu.Unlock()
+
+	if needSync {
+		gen.sync(now)
+	}
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	return now.Add(gen.offset)
+}
+
+// sync query Steam time and update cached offset.
+// Previous offset is kept, if query failed, and query is retried
+// after try again delay from last time tip.
+func (gen *TOTPGenerator) sync(now time.Time) {
 	timeTip, err := gen.FetchTimeTip()
+
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	gen.syncing = false
+
 	if err != nil {
-		return "", errors.Wrap(err, "failed to fetch time tip")
+		tryAgain := gen.tryAgain
+		if tryAgain <= 0 {
+			tryAgain = defaultTryAgain
+		}
+
+		gen.nextSync = now.Add(tryAgain)
+
+		return
+	}
+
+	gen.offset = time.Unix(timeTip.Time, 0).Sub(now).Truncate(time.Second)
+	gen.tryAgain = time.Duration(timeTip.TryAgainSeconds) * time.Second
+
+	// Local time is adjusted, if it's skewed more than tolerance,
+	// so it's probed with adjusted frequency.
+	tolerance := time.Duration(timeTip.SkewToleranceSeconds) * time.Second
+	skewed := gen.offset > tolerance || gen.offset < -tolerance
+
+	probeFrequency := time.Duration(timeTip.ProbeFrequencySeconds) * time.Second
+	if skewed && timeTip.AdjustedTimeProbeFrequencySeconds > 0 {
+		probeFrequency = time.Duration(timeTip.AdjustedTimeProbeFrequencySeconds) * time.Second
+	}
+
+	if probeFrequency <= 0 {
+		probeFrequency = defaultProbeFrequency
 	}
 
-	return gen.GenerateTwoFactorCode(sharedSecret, timeTip.Time)
+	gen.nextSync = now.Add(probeFrequency)
+}
+
+// TwoFactorSynced return generated two-factor code synced by time with Steam
+func (gen *TOTPGenerator) TwoFactorSynced(sharedSecret string) (string, error) {
+	return gen.TwoFactorWindow(sharedSecret, 0)
+}
+
+// TwoFactorWindow return two-factor code for window shifted from current one.
+// Codes of previous (-1) and next (1) windows are useful near windows boundary.
+func (gen *TOTPGenerator) TwoFactorWindow(sharedSecret string, shift int) (string, error) {
+	timestamp := gen.Now().Add(time.Duration(shift) * TwoFactorPeriod).Unix()
+
+	return gen.GenerateTwoFactorCode(sharedSecret, timestamp)
 }
 
 // GenerateTwoFactorCode generate Steam two-factor code using current timestamp as parameter.
@@ -57,7 +171,7 @@ func (gen *TOTPGenerator) GenerateTwoFactorCode(sharedSecret string, currentTime
 	}
 
 	ful := make([]byte, 8)
-	binary.BigEndian.PutUint32(ful[4:], uint32(currentTimestamp/30))
+	binary.BigEndian.PutUint32(ful[4:], uint32(currentTimestamp/int64(TwoFactorPeriod/time.Second)))
 
 	hmacBuf := hmac.New(sha1.New, data)
 	hmacBuf.Write(ful)
@@ -77,8 +191,12 @@ func (gen *TOTPGenerator) GenerateTwoFactorCode(sharedSecret string, currentTime
 
 // FetchTimeTip fetch time from Steam.
 func (gen *TOTPGenerator) FetchTimeTip() (*TimeTip, error) {
+	gen.mu.Lock()
+	queryTimeURL := gen.queryTimeURL
+	gen.mu.Unlock()
+
 	resp, err := gen.cl.Post(
-		"https://api.steampowered.com/ITwoFactorService/QueryTime/v1/",
+		queryTimeURL,
 		"application/x-www-form-urlencoded",
 		nil,
 	)
@@ -86,6 +204,12 @@ func (gen *TOTPGenerator) FetchTimeTip() (*TimeTip, error) {
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(http.StatusText(resp.StatusCode))
+	}
+
 	type Response struct {
 		Inner *TimeTip `json:"response"`
 	}
@@ -95,7 +219,9 @@ func (gen *TOTPGenerator) FetchTimeTip() (*TimeTip, error) {
 		return nil, err
 	}
 
-	resp.Body.Close()
+	if response.Inner == nil {
+		return nil, errors.New("empty time tip")
+	}
 
 	return response.Inner, nil
 }
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTimeServer start QueryTime server, which responds with time shifted by skew from now.
+// Queries fail, if fail is set.
+func newTimeServer(t *testing.T, now time.Time, skew time.Duration, fail *bool) *TOTPGenerator {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if *fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		fmt.Fprintf(w, `{"response":{
+			"server_time":"%d",
+			"skew_tolerance_seconds":"60",
+			"probe_frequency_seconds":3600,
+			"adjusted_time_probe_frequency_seconds":300,
+			"try_again_seconds":90
+		}}`, now.Add(skew).Unix())
+	}))
+
+	t.Cleanup(srv.Close)
+
+	gen := NewTOTPGenerator(srv.Client())
+	gen.SetQueryTimeURL(srv.URL)
+	gen.SetClock(func() time.Time { return now })
+
+	return gen
+}
+
+func TestTOTPGeneratorSync(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name         string
+		skew         time.Duration
+		nextSync     time.Duration
+		expectedSkew time.Duration
+	}{
+		{"within tolerance", 30 * time.Second, time.Hour, 30 * time.Second},
+		{"exceeds tolerance", -2 * time.Minute, 5 * time.Minute, -2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fail := false
+			gen := newTimeServer(t, now, tt.skew, &fail)
+
+			if got := gen.Now(); !got.Equal(now.Add(tt.expectedSkew)) {
+				t.Fatalf("unexpected time %s, expected %s", got, now.Add(tt.expectedSkew))
+			}
+
+			if !gen.nextSync.Equal(now.Add(tt.nextSync)) {
+				t.Fatalf("unexpected next sync %s, expected %s", gen.nextSync, now.Add(tt.nextSync))
+			}
+		})
+	}
+}
+
+func TestTOTPGeneratorSyncFailed(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	fail := true
+	gen := newTimeServer(t, now, time.Minute, &fail)
+
+	gen.Now()
+
+	if !gen.nextSync.Equal(now.Add(defaultTryAgain)) {
+		t.Fatalf("unexpected next sync %s, expected default try again delay", gen.nextSync)
+	}
+
+	// Try again delay of time tip is used, and offset is kept after failure.
+	fail = false
+	gen.SetClock(func() time.Time { return now })
+	gen.Now()
+
+	now = now.Add(time.Hour)
+	gen.SetClock(func() time.Time { return now })
+
+	fail = true
+
+	if got := gen.Now(); !got.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected time %s, expected offset to be kept", got)
+	}
+
+	if !gen.nextSync.Equal(now.Add(90 * time.Second)) {
+		t.Fatalf("unexpected next sync %s, expected try again delay of time tip", gen.nextSync)
+	}
+}
@@ -0,0 +1,22 @@
+package authtickets
+
+import "github.com/furdarius/steamprotocol"
+
+// GameConnectTokensEvent is fired when CMsgClientGameConnectTokens is received.
+// Count is a number of tokens stored after update.
+type GameConnectTokensEvent struct {
+	Count int
+}
+
+// TicketAuthCompleteEvent is fired when CMsgClientTicketAuthComplete is received.
+// It's received for own tickets, when they're used by other side,
+// and for tickets activated with ActivateAuthSessionTicket.
+type TicketAuthCompleteEvent struct {
+	SteamID        uint64
+	OwnerSteamID   uint64
+	GameID         uint64
+	State          uint32
+	Response       steamprotocol.EAuthSessionResponse
+	TicketCRC      uint32
+	TicketSequence uint32
+}
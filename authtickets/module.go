@@ -0,0 +1,298 @@
+// Package authtickets used to create session auth tickets of apps
+// and to validate tickets of other users.
+//
+// Steam sends game connect tokens after logon with ClientGameConnectTokens.
+// Every session auth ticket consumes one token, and contains app ownership ticket.
+// Ticket must be registered with ClientAuthList before it's sent to game server.
+// Steam reports result of ticket validation with ClientTicketAuthComplete.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package authtickets
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"sync"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/apptickets"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// steamPipe is a handle of Steam pipe reported with tickets.
+	// Client has the single pipe.
+	steamPipe = 1
+
+	// Values of CMsgAuthTicket estate.
+	stateOwn     = 0
+	stateForeign = 1
+)
+
+// ErrNoGameConnectTokens is returned when there are no game connect tokens to create ticket.
+var ErrNoGameConnectTokens = errors.New("no game connect tokens")
+
+// Module used to manage session auth tickets.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	appTickets   *apptickets.Module
+
+	mu              sync.Mutex
+	tokens          [][]byte
+	publicIP        uint32
+	connectedAt     time.Time
+	connectionCount uint32
+	tickets         []*protobuf.CMsgAuthTicket
+	sequence        uint32
+}
+
+// NewModule initialize new instance of authtickets Module.
+// appTickets is used to request ownership tickets, which are included in auth tickets.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager, appTickets *apptickets.Module) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		appTickets:   appTickets,
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientLogOnResponse:
+		return m.handleLogOnResponse(p)
+	case steamprotocol.EMsg_ClientGameConnectTokens:
+		return m.handleGameConnectTokens(p)
+	case steamprotocol.EMsg_ClientTicketAuthComplete:
+		return m.handleTicketAuthComplete(p)
+	}
+
+	return nil
+}
+
+// GameConnectTokens return count of stored game connect tokens.
+func (m *Module) GameConnectTokens() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.tokens)
+}
+
+// GetAuthSessionTicket create session auth ticket of app for current user,
+// and register it with Steam, so game server is able to validate it.
+func (m *Module) GetAuthSessionTicket(ctx context.Context, appID uint32) (*AuthTicket, error) {
+	ownership, err := m.appTickets.GetAppOwnershipTicket(ctx, appID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app ownership ticket")
+	}
+
+	m.mu.Lock()
+
+	if len(m.tokens) == 0 {
+		m.mu.Unlock()
+
+		return nil, ErrNoGameConnectTokens
+	}
+
+	token := m.tokens[0]
+	m.tokens = m.tokens[1:]
+	m.connectionCount++
+
+	ticket := buildTicket(appID, token, m.publicIP, time.Since(m.connectedAt), m.connectionCount, ownership)
+
+	m.tickets = append(m.tickets, &protobuf.CMsgAuthTicket{
+		Estate:     proto.Uint32(stateOwn),
+		Gameid:     proto.Uint64(uint64(appID)),
+		HSteamPipe: proto.Uint32(steamPipe),
+		TicketCrc:  proto.Uint32(ticket.CRC),
+		Ticket:     ticket.Data,
+	})
+
+	m.mu.Unlock()
+
+	err = m.sendAuthList(ctx, ticket.CRC)
+	if err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// ActivateAuthSessionTicket register ticket of other user to validate it.
+// Result of validation is fired as TicketAuthCompleteEvent.
+func (m *Module) ActivateAuthSessionTicket(ctx context.Context, appID uint32, steamID uint64, ticket []byte) error {
+	crc := crc32.ChecksumIEEE(ticket)
+
+	m.mu.Lock()
+	m.tickets = append(m.tickets, &protobuf.CMsgAuthTicket{
+		Estate:     proto.Uint32(stateForeign),
+		Steamid:    proto.Uint64(steamID),
+		Gameid:     proto.Uint64(uint64(appID)),
+		HSteamPipe: proto.Uint32(steamPipe),
+		TicketCrc:  proto.Uint32(crc),
+		Ticket:     ticket,
+	})
+	m.mu.Unlock()
+
+	return m.sendAuthList(ctx, crc)
+}
+
+// CancelAuthTicket cancel own or activated ticket by its crc.
+func (m *Module) CancelAuthTicket(ctx context.Context, crc uint32) error {
+	m.mu.Lock()
+
+	found := false
+	tickets := m.tickets[:0]
+
+	for _, t := range m.tickets {
+		if t.GetTicketCrc() == crc {
+			found = true
+
+			continue
+		}
+
+		tickets = append(tickets, t)
+	}
+
+	m.tickets = tickets
+
+	m.mu.Unlock()
+
+	if !found {
+		return fmt.Errorf("ticket with crc %d is not active", crc)
+	}
+
+	return m.sendAuthList(ctx, 0)
+}
+
+// sendAuthList send all active tickets to Steam.
+// If crc isn't zero, ack is checked to contain ticket with it.
+func (m *Module) sendAuthList(ctx context.Context, crc uint32) error {
+	m.mu.Lock()
+
+	m.sequence++
+
+	msg := &protobuf.CMsgClientAuthList{
+		TokensLeft:      proto.Uint32(uint32(len(m.tokens))),
+		LastRequestSeq:  proto.Uint32(m.sequence),
+		MessageSequence: proto.Uint32(m.sequence),
+	}
+
+	apps := make(map[uint32]struct{})
+	for _, t := range m.tickets {
+		appID := uint32(t.GetGameid())
+		if _, ok := apps[appID]; ok {
+			continue
+		}
+
+		apps[appID] = struct{}{}
+		msg.AppIds = append(msg.AppIds, appID)
+	}
+
+	// Message is marshaled outside of lock, so tickets are copied.
+	msg.Tickets = append([]*protobuf.CMsgAuthTicket(nil), m.tickets...)
+
+	m.mu.Unlock()
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientAuthList, msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to send auth list")
+	}
+
+	var ack protobuf.CMsgClientAuthListAck
+
+	_, err = messages.ReadProto(p.Data, &ack)
+	if err != nil {
+		return errors.Wrap(err, "failed to read auth list ack")
+	}
+
+	if crc == 0 {
+		return nil
+	}
+
+	for _, c := range ack.GetTicketCrc() {
+		if c == crc {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ticket with crc %d wasn't accepted", crc)
+}
+
+func (m *Module) handleLogOnResponse(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientLogonResponse
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read logon response")
+	}
+
+	if steamprotocol.EResult(msg.GetEresult()) != steamprotocol.EResult_OK {
+		return nil
+	}
+
+	// Tickets aren't kept by Steam between sessions.
+	m.mu.Lock()
+	m.publicIP = msg.GetPublicIp()
+	m.connectedAt = time.Now()
+	m.tickets = nil
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Module) handleGameConnectTokens(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientGameConnectTokens
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read game connect tokens")
+	}
+
+	m.mu.Lock()
+
+	m.tokens = append(m.tokens, msg.GetTokens()...)
+
+	maxTokens := int(msg.GetMaxTokensToKeep())
+	if len(m.tokens) > maxTokens {
+		m.tokens = m.tokens[len(m.tokens)-maxTokens:]
+	}
+
+	count := len(m.tokens)
+
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(GameConnectTokensEvent{
+		Count: count,
+	})
+}
+
+func (m *Module) handleTicketAuthComplete(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientTicketAuthComplete
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read ticket auth complete")
+	}
+
+	return m.eventManager.FireEvent(TicketAuthCompleteEvent{
+		SteamID:        msg.GetSteamId(),
+		OwnerSteamID:   msg.GetOwnerSteamId(),
+		GameID:         msg.GetGameId(),
+		State:          msg.GetEstate(),
+		Response:       steamprotocol.EAuthSessionResponse(msg.GetEauthSessionResponse()),
+		TicketCRC:      msg.GetTicketCrc(),
+		TicketSequence: msg.GetTicketSequence(),
+	})
+}
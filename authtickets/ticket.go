@@ -0,0 +1,65 @@
+package authtickets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"time"
+)
+
+// sessionHeaderSize is a size of session header of auth ticket.
+const sessionHeaderSize = 24
+
+// AuthTicket is a session auth ticket, which is sent to game server
+// or other user to prove ownership of app.
+//
+// Binary layout, all integers are little endian:
+//
+//	uint32 game connect token size, game connect token
+//	uint32 session header size (24)
+//	uint32 unknown, always 1
+//	uint32 unknown, always 2
+//	uint32 public ip of client
+//	uint32 padding
+//	uint32 milliseconds since connection
+//	uint32 connection count
+//	app ownership ticket with signature
+type AuthTicket struct {
+	AppID uint32
+	CRC   uint32
+	Data  []byte
+}
+
+func buildTicket(appID uint32, token []byte, publicIP uint32, sinceConnect time.Duration, connectionCount uint32, ownership []byte) *AuthTicket {
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(token)))
+	buf.Write(token)
+
+	binary.Write(&buf, binary.LittleEndian, struct {
+		Size            uint32
+		Unknown1        uint32
+		Unknown2        uint32
+		PublicIP        uint32
+		Padding         uint32
+		SinceConnect    uint32
+		ConnectionCount uint32
+	}{
+		Size:            sessionHeaderSize,
+		Unknown1:        1,
+		Unknown2:        2,
+		PublicIP:        publicIP,
+		SinceConnect:    uint32(sinceConnect / time.Millisecond),
+		ConnectionCount: connectionCount,
+	})
+
+	buf.Write(ownership)
+
+	data := buf.Bytes()
+
+	return &AuthTicket{
+		AppID: appID,
+		CRC:   crc32.ChecksumIEEE(data),
+		Data:  data,
+	}
+}
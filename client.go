@@ -5,11 +5,14 @@ import (
 	"encoding/binary"
 	"io"
 	"net"
+	"sync"
 
 	"time"
 
 	"fmt"
 
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 )
 
@@ -45,14 +48,25 @@ type Client struct {
 	conn         net.Conn
 	eventManager *EventManager
 	crypto       Encryptor
+	sessionMu    sync.RWMutex
+	session      Session
+	jobsMu       sync.Mutex
+	jobs         map[uint64]*Job
+	lastJobID    uint64
 }
 
 // NewClient initialize new instance of Client.
+// Client listen packets from eventManager to pass responses to jobs.
 func NewClient(conn net.Conn, eventManager *EventManager) *Client {
-	return &Client{
+	c := &Client{
 		conn:         conn,
 		eventManager: eventManager,
+		jobs:         make(map[uint64]*Job),
 	}
+
+	eventManager.OnPacket(c.handleJobPacket)
+
+	return c
 }
 
 // Listen start to read connection with Steam server.
@@ -125,12 +139,47 @@ func (c *Client) Listen() error {
 	}
 }
 
+// Send is used to write proto message with given EMsg to Steam connection.
+// Header is stamped with current session, so it mustn't be filled by caller.
+func (c *Client) Send(eMsg EMsg, msg proto.Message) error {
+	return c.SendWithHeader(eMsg, &protobuf.CMsgProtoBufHeader{}, msg)
+}
+
+// SendWithHeader is same as Send, but allows to set custom header fields,
+// like target job id of response.
+func (c *Client) SendWithHeader(eMsg EMsg, header *protobuf.CMsgProtoBufHeader, msg proto.Message) error {
+	buf := new(bytes.Buffer)
+
+	err := writeProtoHeader(buf, uint32(eMsg), header)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize header")
+	}
+
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal msg")
+	}
+
+	_, err = buf.Write(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to append msg to buffer")
+	}
+
+	return c.Write(buf.Bytes())
+}
+
 // Write is used to write byte array to Steam connection.
+// Proto and extended headers of data are stamped with current session, if it's set.
 func (c *Client) Write(data []byte) (err error) {
 	if c.conn == nil {
 		return errors.New("connection is not defined")
 	}
 
+	data, err = c.stampSession(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to stamp session")
+	}
+
 	if c.crypto != nil {
 		data, err = c.crypto.Encrypt(data)
 		if err != nil {
@@ -0,0 +1,294 @@
+// Package cloud used to list, read, write and delete Steam Cloud files of apps.
+//
+// Metadata of files is requested from CM with legacy UFS messages:
+// ClientUFSGetFileListForApp (client->server): Request files of apps.
+// ClientUFSGetFileListForAppResponse (server->client): Files with SHA, size and timestamp.
+// ClientUFSGetSingleFileInfo (client->server): Request single file info.
+// ClientUFSGetSingleFileInfoResponse (server->client): File info.
+//
+// Contents of files are transferred over HTTP, using locations returned by Cloud unified service:
+// 1. Cloud.ClientFileDownload returns url and headers of file, which is downloaded with GET.
+// 2. Cloud.ClientBeginFileUpload returns block requests, which are sent in any order.
+// 3. Cloud.ClientCommitFileUpload commits file after all blocks are uploaded.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package cloud
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// enumeratePageSize is a count of files requested by one Cloud.EnumerateUserFiles call.
+const enumeratePageSize = 500
+
+// File is a Steam Cloud file of app.
+type File struct {
+	AppID            uint32
+	Name             string
+	SHA              []byte
+	Timestamp        time.Time
+	Size             uint32
+	IsExplicitDelete bool
+	PlatformsToSync  uint32
+}
+
+// UserFile is a file of user, which is enumerated by Cloud service.
+// It contains UGC id and url, which are used to share file.
+type UserFile struct {
+	AppID          uint32
+	UGCID          uint64
+	Name           string
+	Timestamp      time.Time
+	Size           uint32
+	URL            string
+	CreatorSteamID uint64
+}
+
+// ErrSHAMismatch is returned, when SHA of downloaded file isn't equal to SHA reported by Steam.
+var ErrSHAMismatch = errors.New("sha of file doesn't match")
+
+// Module used to access Steam Cloud files.
+type Module struct {
+	cl     *steamprotocol.Client
+	httpCl *http.Client
+}
+
+// NewModule initialize new instance of cloud Module.
+// httpCl is used to transfer contents of files, http.DefaultClient is used, if it's nil.
+func NewModule(cl *steamprotocol.Client, httpCl *http.Client) *Module {
+	if httpCl == nil {
+		httpCl = http.DefaultClient
+	}
+
+	return &Module{
+		cl:     cl,
+		httpCl: httpCl,
+	}
+}
+
+// ListFiles return cloud files of apps.
+func (m *Module) ListFiles(ctx context.Context, appIDs ...uint32) ([]File, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientUFSGetFileListForApp, &protobuf.CMsgClientUFSGetFileListForApp{
+		AppsToQuery:      appIDs,
+		SendPathPrefixes: proto.Bool(false),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request file list")
+	}
+
+	var msg protobuf.CMsgClientUFSGetFileListForAppResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file list")
+	}
+
+	files := make([]File, 0, len(msg.GetFiles()))
+	for _, f := range msg.GetFiles() {
+		files = append(files, File{
+			AppID:            f.GetAppId(),
+			Name:             f.GetFileName(),
+			SHA:              f.GetShaFile(),
+			Timestamp:        steamprotocol.UnixTime(uint32(f.GetTimeStamp())),
+			Size:             f.GetRawFileSize(),
+			IsExplicitDelete: f.GetIsExplicitDelete(),
+			PlatformsToSync:  f.GetPlatformsToSync(),
+		})
+	}
+
+	return files, nil
+}
+
+// FileInfo return info of single cloud file.
+func (m *Module) FileInfo(ctx context.Context, appID uint32, name string) (*File, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientUFSGetSingleFileInfo, &protobuf.CMsgClientUFSGetSingleFileInfo{
+		AppId:    proto.Uint32(appID),
+		FileName: proto.String(name),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request file info")
+	}
+
+	var msg protobuf.CMsgClientUFSGetSingleFileInfoResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file info")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("file info request failed with result %s", result.String())
+	}
+
+	return &File{
+		AppID:            msg.GetAppId(),
+		Name:             msg.GetFileName(),
+		SHA:              msg.GetShaFile(),
+		Timestamp:        steamprotocol.UnixTime(uint32(msg.GetTimeStamp())),
+		Size:             msg.GetRawFileSize(),
+		IsExplicitDelete: msg.GetIsExplicitDelete(),
+	}, nil
+}
+
+// EnumerateUserFiles return all files of app enumerated by Cloud service.
+// Files are requested page by page, until all of them are received.
+func (m *Module) EnumerateUserFiles(ctx context.Context, appID uint32) ([]UserFile, error) {
+	var files []UserFile
+
+	for {
+		var resp unified.CCloud_EnumerateUserFiles_Response
+
+		err := m.cl.CallService(ctx, "Cloud.EnumerateUserFiles#1", &unified.CCloud_EnumerateUserFiles_Request{
+			Appid:           proto.Uint32(appID),
+			ExtendedDetails: proto.Bool(true),
+			Count:           proto.Uint32(enumeratePageSize),
+			StartIndex:      proto.Uint32(uint32(len(files))),
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range resp.GetFiles() {
+			files = append(files, UserFile{
+				AppID:          f.GetAppid(),
+				UGCID:          f.GetUgcid(),
+				Name:           f.GetFilename(),
+				Timestamp:      steamprotocol.UnixTime(uint32(f.GetTimestamp())),
+				Size:           f.GetFileSize(),
+				URL:            f.GetUrl(),
+				CreatorSteamID: f.GetSteamidCreator(),
+			})
+		}
+
+		if len(resp.GetFiles()) == 0 || uint32(len(files)) >= resp.GetTotalFiles() {
+			return files, nil
+		}
+	}
+}
+
+// ReadFile download contents of cloud file.
+// ErrSHAMismatch is returned, if contents are corrupted.
+func (m *Module) ReadFile(ctx context.Context, appID uint32, name string) ([]byte, error) {
+	var resp unified.CCloud_ClientFileDownload_Response
+
+	err := m.cl.CallService(ctx, "Cloud.ClientFileDownload#1", &unified.CCloud_ClientFileDownload_Request{
+		Appid:    proto.Uint32(appID),
+		Filename: proto.String(name),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetEncrypted() {
+		return nil, errors.New("encrypted files are not supported")
+	}
+
+	headers := make(http.Header)
+	for _, h := range resp.GetRequestHeaders() {
+		headers.Add(h.GetName(), h.GetValue())
+	}
+
+	data, err := m.download(ctx, blockURL(resp.GetUseHttps(), resp.GetUrlHost(), resp.GetUrlPath()), headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download file")
+	}
+
+	// File is stored as zip archive, when it's compressed
+	if resp.GetFileSize() != resp.GetRawFileSize() {
+		data, err = unzip(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress file")
+		}
+	}
+
+	if uint32(len(data)) != resp.GetRawFileSize() {
+		return nil, fmt.Errorf("file size %d doesn't match %d", len(data), resp.GetRawFileSize())
+	}
+
+	sha := sha1.Sum(data)
+	if string(sha[:]) != string(resp.GetShaFile()) {
+		return nil, ErrSHAMismatch
+	}
+
+	return data, nil
+}
+
+// WriteFile upload contents of cloud file.
+// Upload is committed only after all blocks are transferred,
+// otherwise it's canceled and previous version of file is kept.
+func (m *Module) WriteFile(ctx context.Context, appID uint32, name string, data []byte) error {
+	sha := sha1.Sum(data)
+
+	var resp unified.CCloud_ClientBeginFileUpload_Response
+
+	err := m.cl.CallService(ctx, "Cloud.ClientBeginFileUpload#1", &unified.CCloud_ClientBeginFileUpload_Request{
+		Appid:       proto.Uint32(appID),
+		FileSize:    proto.Uint32(uint32(len(data))),
+		RawFileSize: proto.Uint32(uint32(len(data))),
+		FileSha:     sha[:],
+		TimeStamp:   proto.Uint64(uint64(time.Now().Unix())),
+		Filename:    proto.String(name),
+		CellId:      proto.Uint32(m.cl.Session().CellID),
+		CanEncrypt:  proto.Bool(false),
+	}, &resp)
+	if err != nil {
+		return err
+	}
+
+	uploadErr := m.uploadBlocks(ctx, resp.GetBlockRequests(), data)
+
+	committed, err := m.commitUpload(ctx, appID, name, sha[:], uploadErr == nil)
+	if uploadErr != nil {
+		return errors.Wrap(uploadErr, "failed to upload file")
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to commit upload")
+	}
+
+	if !committed {
+		return errors.New("file upload wasn't committed")
+	}
+
+	return nil
+}
+
+// DeleteFile delete cloud file.
+func (m *Module) DeleteFile(ctx context.Context, appID uint32, name string) error {
+	var resp unified.CCloud_ClientDeleteFile_Response
+
+	return m.cl.CallService(ctx, "Cloud.ClientDeleteFile#1", &unified.CCloud_ClientDeleteFile_Request{
+		Appid:            proto.Uint32(appID),
+		Filename:         proto.String(name),
+		IsExplicitDelete: proto.Bool(true),
+	}, &resp)
+}
+
+func (m *Module) commitUpload(ctx context.Context, appID uint32, name string, sha []byte, succeeded bool) (bool, error) {
+	var resp unified.CCloud_ClientCommitFileUpload_Response
+
+	err := m.cl.CallService(ctx, "Cloud.ClientCommitFileUpload#1", &unified.CCloud_ClientCommitFileUpload_Request{
+		TransferSucceeded: proto.Bool(succeeded),
+		Appid:             proto.Uint32(appID),
+		FileSha:           sha,
+		Filename:          proto.String(name),
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetFileCommitted(), nil
+}
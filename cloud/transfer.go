@@ -0,0 +1,130 @@
+package cloud
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/pkg/errors"
+)
+
+// httpMethods maps EHTTPMethod of block request to HTTP method.
+var httpMethods = map[int32]string{
+	1: http.MethodGet,
+	2: http.MethodHead,
+	3: http.MethodPost,
+	4: http.MethodPut,
+	5: http.MethodDelete,
+	6: http.MethodOptions,
+	7: http.MethodPatch,
+}
+
+func blockURL(https bool, host, path string) string {
+	if https {
+		return "https://" + host + path
+	}
+
+	return "http://" + host + path
+}
+
+func (m *Module) download(ctx context.Context, url string, headers http.Header) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header = headers
+
+	resp, err := m.httpCl.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (m *Module) uploadBlocks(ctx context.Context, blocks []*unified.ClientCloudFileUploadBlockDetails, data []byte) error {
+	for _, b := range blocks {
+		err := m.uploadBlock(ctx, b, data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upload block at offset %d", b.GetBlockOffset())
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) uploadBlock(ctx context.Context, b *unified.ClientCloudFileUploadBlockDetails, data []byte) error {
+	method, ok := httpMethods[b.GetHttpMethod()]
+	if !ok {
+		return fmt.Errorf("unknown http method %d", b.GetHttpMethod())
+	}
+
+	body := b.GetExplicitBodyData()
+	if body == nil {
+		start := b.GetBlockOffset()
+		end := start + uint64(b.GetBlockLength())
+
+		if end > uint64(len(data)) {
+			return fmt.Errorf("block exceeds file size %d", len(data))
+		}
+
+		body = data[start:end]
+	}
+
+	req, err := http.NewRequest(method, blockURL(b.GetUseHttps(), b.GetUrlHost(), b.GetUrlPath()), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	for _, h := range b.GetRequestHeaders() {
+		req.Header.Add(h.GetName(), h.GetValue())
+	}
+
+	resp, err := m.httpCl.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+
+	return err
+}
+
+// unzip return contents of the only file in zip archive.
+func unzip(data []byte) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.File) != 1 {
+		return nil, fmt.Errorf("archive contains %d files, expected 1", len(r.File))
+	}
+
+	f, err := r.File[0].Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
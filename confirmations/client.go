@@ -0,0 +1,293 @@
+// Package confirmations used to list, accept and cancel Steam mobile confirmations.
+//
+// Mobile confirmations are required for trades and market listings of accounts with
+// mobile authenticator. Every mobileconf request is signed with confirmation key,
+// generated from identity secret, current time and operation tag.
+//
+// Requests require authenticated web session, so http.Client must have cookie jar
+// of websession.Session.
+package confirmations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/furdarius/steamprotocol/auth"
+	"github.com/pkg/errors"
+)
+
+// DefaultBaseURL is Steam Community url.
+const DefaultBaseURL = "https://steamcommunity.com"
+
+// ErrNeedAuth is returned, when web session isn't authenticated.
+var ErrNeedAuth = errors.New("web session is not authenticated")
+
+// Confirmation is a pending mobile confirmation.
+type Confirmation struct {
+	ID           uint64
+	Key          uint64
+	CreatorID    uint64
+	Type         int
+	TypeName     string
+	Headline     string
+	Summary      []string
+	CreationTime time.Time
+}
+
+// Client used to call mobileconf endpoints.
+type Client struct {
+	httpCl         *http.Client
+	baseURL        string
+	steamID        uint64
+	identitySecret string
+	deviceID       string
+	now            func() time.Time
+}
+
+// NewClient initialize new instance of Client.
+// baseURL is Steam Community url, DefaultBaseURL is used if it's empty.
+// http.DefaultClient is used, if httpCl is nil.
+func NewClient(httpCl *http.Client, baseURL string, steamID uint64, identitySecret string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if httpCl == nil {
+		httpCl = http.DefaultClient
+	}
+
+	return &Client{
+		httpCl:         httpCl,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		steamID:        steamID,
+		identitySecret: identitySecret,
+		deviceID:       auth.DeviceID(steamID),
+		now:            time.Now,
+	}
+}
+
+// SetClock change function used to get time for confirmation keys.
+// Keys must be generated with Steam time, so TOTPGenerator.Now can be used.
+func (c *Client) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// List return pending confirmations.
+func (c *Client) List(ctx context.Context) ([]Confirmation, error) {
+	params, err := c.params(auth.ConfirmationTagList)
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Success  bool `json:"success"`
+		NeedAuth bool `json:"needauth"`
+		Conf     []struct {
+			ID           uint64   `json:"id,string"`
+			Nonce        uint64   `json:"nonce,string"`
+			CreatorID    uint64   `json:"creator_id,string"`
+			Type         int      `json:"type"`
+			TypeName     string   `json:"type_name"`
+			Headline     string   `json:"headline"`
+			Summary      []string `json:"summary"`
+			CreationTime int64    `json:"creation_time"`
+		} `json:"conf"`
+	}
+
+	var response Response
+
+	err = c.do(ctx, http.MethodGet, "/mobileconf/getlist", params, &response)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get confirmations list")
+	}
+
+	if response.NeedAuth {
+		return nil, ErrNeedAuth
+	}
+
+	if !response.Success {
+		return nil, errors.New("failed to get confirmations list")
+	}
+
+	result := make([]Confirmation, 0, len(response.Conf))
+	for _, conf := range response.Conf {
+		result = append(result, Confirmation{
+			ID:           conf.ID,
+			Key:          conf.Nonce,
+			CreatorID:    conf.CreatorID,
+			Type:         conf.Type,
+			TypeName:     conf.TypeName,
+			Headline:     conf.Headline,
+			Summary:      conf.Summary,
+			CreationTime: time.Unix(conf.CreationTime, 0),
+		})
+	}
+
+	return result, nil
+}
+
+// Details return html with details of confirmation.
+func (c *Client) Details(ctx context.Context, conf Confirmation) (string, error) {
+	params, err := c.params(auth.ConfirmationTagDetails)
+	if err != nil {
+		return "", err
+	}
+
+	type Response struct {
+		Success bool   `json:"success"`
+		HTML    string `json:"html"`
+	}
+
+	var response Response
+
+	err = c.do(ctx, http.MethodGet, "/mobileconf/details/"+strconv.FormatUint(conf.ID, 10), params, &response)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get confirmation details")
+	}
+
+	if !response.Success {
+		return "", fmt.Errorf("failed to get details of confirmation %d", conf.ID)
+	}
+
+	return response.HTML, nil
+}
+
+// Accept accept confirmation.
+func (c *Client) Accept(ctx context.Context, conf Confirmation) error {
+	return c.op(ctx, auth.ConfirmationTagAllow, "allow", conf)
+}
+
+// Cancel cancel confirmation.
+func (c *Client) Cancel(ctx context.Context, conf Confirmation) error {
+	return c.op(ctx, auth.ConfirmationTagCancel, "cancel", conf)
+}
+
+// AcceptMulti accept several confirmations with one request.
+func (c *Client) AcceptMulti(ctx context.Context, confs []Confirmation) error {
+	return c.multiOp(ctx, auth.ConfirmationTagAllow, "allow", confs)
+}
+
+// CancelMulti cancel several confirmations with one request.
+func (c *Client) CancelMulti(ctx context.Context, confs []Confirmation) error {
+	return c.multiOp(ctx, auth.ConfirmationTagCancel, "cancel", confs)
+}
+
+func (c *Client) op(ctx context.Context, tag string, op string, conf Confirmation) error {
+	params, err := c.params(tag)
+	if err != nil {
+		return err
+	}
+
+	params.Set("op", op)
+	params.Set("cid", strconv.FormatUint(conf.ID, 10))
+	params.Set("ck", strconv.FormatUint(conf.Key, 10))
+
+	type Response struct {
+		Success bool `json:"success"`
+	}
+
+	var response Response
+
+	err = c.do(ctx, http.MethodGet, "/mobileconf/ajaxop", params, &response)
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s confirmation", op)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("failed to %s confirmation %d", op, conf.ID)
+	}
+
+	return nil
+}
+
+func (c *Client) multiOp(ctx context.Context, tag string, op string, confs []Confirmation) error {
+	params, err := c.params(tag)
+	if err != nil {
+		return err
+	}
+
+	params.Set("op", op)
+	for _, conf := range confs {
+		params.Add("cid[]", strconv.FormatUint(conf.ID, 10))
+		params.Add("ck[]", strconv.FormatUint(conf.Key, 10))
+	}
+
+	type Response struct {
+		Success bool `json:"success"`
+	}
+
+	var response Response
+
+	err = c.do(ctx, http.MethodPost, "/mobileconf/multiajaxop", params, &response)
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s confirmations", op)
+	}
+
+	if !response.Success {
+		return fmt.Errorf("failed to %s %d confirmations", op, len(confs))
+	}
+
+	return nil
+}
+
+// params return query params signed with confirmation key for tag.
+func (c *Client) params(tag string) (url.Values, error) {
+	timestamp := c.now().Unix()
+
+	key, err := auth.GenerateConfirmationKey(c.identitySecret, timestamp, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate confirmation key")
+	}
+
+	return url.Values{
+		"p":   {c.deviceID},
+		"a":   {strconv.FormatUint(c.steamID, 10)},
+		"k":   {key},
+		"t":   {strconv.FormatInt(timestamp, 10)},
+		"m":   {"android"},
+		"tag": {tag},
+	}, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, params url.Values, response interface{}) error {
+	var (
+		req *http.Request
+		err error
+	)
+
+	if method == http.MethodPost {
+		req, err = http.NewRequest(method, c.baseURL+path, strings.NewReader(params.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequest(method, c.baseURL+path+"?"+params.Encode(), nil)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := c.httpCl.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(http.StatusText(resp.StatusCode))
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package steamprotocol
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// UnixTime convert unix timestamp used in messages to time.
+// Zero timestamp means, that time isn't set, so zero time is returned for it.
+func UnixTime(timestamp uint32) time.Time {
+	if timestamp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(timestamp), 0)
+}
+
+// IPv4 convert ip stored in messages as uint32 to net.IP.
+// The first octet is the highest byte. Zero ip means, that ip isn't set, so nil is returned for it.
+func IPv4(ip uint32) net.IP {
+	if ip == 0 {
+		return nil
+	}
+
+	buf := make([]byte, net.IPv4len)
+	binary.BigEndian.PutUint32(buf, ip)
+
+	return net.IP(buf)
+}
@@ -0,0 +1,176 @@
+// Package credentials used to audit account credentials with Credentials unified service.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package credentials
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+)
+
+// Authentication is a Steam Guard authentication of machine.
+type Authentication struct {
+	EnabledAt   time.Time
+	IsWebCookie bool
+	IP          net.IP
+	GeolocInfo  string
+	Remembered  bool
+	MachineName string
+	Status      int32
+}
+
+// Session is a machine, which was authorized by Steam Guard.
+type Session struct {
+	MachineID       uint64
+	MachineName     string
+	EnabledAt       time.Time
+	PublicIP        net.IP
+	Authentications []Authentication
+}
+
+// SteamGuardDetails contains Steam Guard status of account.
+type SteamGuardDetails struct {
+	Enabled            bool
+	EnabledAt          time.Time
+	TwoFactorEnabled   bool
+	TwoFactorEnabledAt time.Time
+	PhoneVerified      bool
+	Sessions           []Session
+}
+
+// CredentialChangeTimes contains times of last credentials changes.
+// Zero time means credential was never changed.
+type CredentialChangeTimes struct {
+	LastPasswordChange time.Time
+	LastEmailChange    time.Time
+	LastPasswordReset  time.Time
+}
+
+// AccountAuthSecret is secret of account used by Steam client.
+type AccountAuthSecret struct {
+	ID     int32
+	Secret []byte
+}
+
+// Module used to call Credentials service methods.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of credentials Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// TestAvailablePassword reports whether password is valid for account.
+func (m *Module) TestAvailablePassword(ctx context.Context, password string) (bool, error) {
+	var resp unified.CCredentials_TestAvailablePassword_Response
+
+	err := m.cl.CallService(ctx, "Credentials.TestAvailablePassword#1", &unified.CCredentials_TestAvailablePassword_Request{
+		Password: proto.String(password),
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetIsValid(), nil
+}
+
+// GetSteamGuardDetails return Steam Guard status and authorized machines.
+func (m *Module) GetSteamGuardDetails(ctx context.Context) (*SteamGuardDetails, error) {
+	var resp unified.CCredentials_GetSteamGuardDetails_Response
+
+	err := m.cl.CallService(ctx, "Credentials.GetSteamGuardDetails#1", &unified.CCredentials_GetSteamGuardDetails_Request{
+		IncludeNewAuthentications: proto.Bool(true),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &SteamGuardDetails{
+		Enabled:            resp.GetIsSteamguardEnabled(),
+		EnabledAt:          steamprotocol.UnixTime(resp.GetTimestampSteamguardEnabled()),
+		TwoFactorEnabled:   resp.GetIsTwofactorEnabled(),
+		TwoFactorEnabledAt: steamprotocol.UnixTime(resp.GetTimestampTwofactorEnabled()),
+		PhoneVerified:      resp.GetIsPhoneVerified(),
+	}
+
+	for _, s := range resp.GetSessionData() {
+		session := Session{
+			MachineID:   s.GetMachineId(),
+			MachineName: s.GetMachineNameUserchosen(),
+			EnabledAt:   steamprotocol.UnixTime(s.GetTimestampMachineSteamguardEnabled()),
+			PublicIP:    steamprotocol.IPv4(s.GetPublicIpv4()),
+		}
+
+		for _, a := range s.GetNewauthentication() {
+			session.Authentications = append(session.Authentications, Authentication{
+				EnabledAt:   steamprotocol.UnixTime(a.GetTimestampSteamguardEnabled()),
+				IsWebCookie: a.GetIsWebCookie(),
+				IP:          steamprotocol.IPv4(uint32(a.GetIpaddress())),
+				GeolocInfo:  a.GetGeolocInfo(),
+				Remembered:  a.GetIsRemembered(),
+				MachineName: a.GetMachineNameUserSupplied(),
+				Status:      a.GetStatus(),
+			})
+		}
+
+		details.Sessions = append(details.Sessions, session)
+	}
+
+	return details, nil
+}
+
+// LastCredentialChangeTime return times of last password and email changes.
+func (m *Module) LastCredentialChangeTime(ctx context.Context) (*CredentialChangeTimes, error) {
+	var resp unified.CCredentials_LastCredentialChangeTime_Response
+
+	err := m.cl.CallService(ctx, "Credentials.GetCredentialChangeTimeDetails#1", &unified.CCredentials_LastCredentialChangeTime_Request{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialChangeTimes{
+		LastPasswordChange: steamprotocol.UnixTime(resp.GetTimestampLastPasswordChange()),
+		LastEmailChange:    steamprotocol.UnixTime(resp.GetTimestampLastEmailChange()),
+		LastPasswordReset:  steamprotocol.UnixTime(resp.GetTimestampLastPasswordReset()),
+	}, nil
+}
+
+// GetAccountAuthSecret return account auth secret.
+func (m *Module) GetAccountAuthSecret(ctx context.Context) (*AccountAuthSecret, error) {
+	var resp unified.CCredentials_GetAccountAuthSecret_Response
+
+	err := m.cl.CallService(ctx, "Credentials.GetAccountAuthSecret#1", &unified.CCredentials_GetAccountAuthSecret_Request{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountAuthSecret{
+		ID:     resp.GetSecretId(),
+		Secret: resp.GetSecret(),
+	}, nil
+}
+
+// ValidateEmailAddress validate email address with token from validation email,
+// and report whether it was validated.
+func (m *Module) ValidateEmailAddress(ctx context.Context, stoken string) (bool, error) {
+	var resp unified.CCredentials_ValidateEmailAddress_Response
+
+	err := m.cl.CallService(ctx, "Credentials.ValidateEmailAddress#1", &unified.CCredentials_ValidateEmailAddress_Request{
+		Stoken: proto.String(stoken),
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.GetWasValidated(), nil
+}
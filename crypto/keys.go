@@ -48,7 +48,9 @@ var publicKeys = map[steamprotocol.EUniverse][]byte{
 	},
 }
 
-func getPublicKey(universe steamprotocol.EUniverse) (*rsa.PublicKey, error) {
+// PublicKey return RSA public key of universe.
+// It's used to encrypt session keys, generated by client.
+func PublicKey(universe steamprotocol.EUniverse) (*rsa.PublicKey, error) {
 	bytes, ok := publicKeys[universe]
 	if !ok {
 		return nil, fmt.Errorf("failed to find public key for universe %s", universe.String())
@@ -96,7 +96,7 @@ func (m *Module) handleChannelEncryptRequest(p *steamprotocol.Packet) error {
 		return fmt.Errorf("invalid protocol version %d", msg.ProtocolVersion)
 	}
 
-	pub, err := getPublicKey(steamprotocol.EUniverse_Public)
+	pub, err := PublicKey(steamprotocol.EUniverse_Public)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get public key for universe %v", msg.Universe)
 	}
@@ -0,0 +1,27 @@
+package deviceauth
+
+// LockedLibrary is a shared library, which is used by borrower now.
+type LockedLibrary struct {
+	OwnerID  uint32
+	LockedBy uint32
+}
+
+// SharedLibraryLockStatusEvent is fired when CMsgClientSharedLibraryLockStatus is received.
+// OwnLibraryLockedBy is account id of borrower, which plays games from own library, or 0.
+type SharedLibraryLockStatusEvent struct {
+	LockedLibraries    []LockedLibrary
+	OwnLibraryLockedBy uint32
+}
+
+// StopApp is an app, which must be stopped, because owner of library started playing.
+type StopApp struct {
+	AppID   uint32
+	OwnerID uint32
+}
+
+// SharedLibraryStopPlayingEvent is fired when CMsgClientSharedLibraryStopPlaying is received.
+// Borrower has SecondsLeft to stop playing Apps.
+type SharedLibraryStopPlayingEvent struct {
+	SecondsLeft int32
+	Apps        []StopApp
+}
@@ -0,0 +1,344 @@
+// Package deviceauth used to manage family sharing between accounts.
+//
+// Owner of library authorizes devices of borrowers, and borrowers use owner library on them.
+// Authorized devices and borrowers are managed with DeviceAuth unified service.
+// Device of current client is authorized with legacy messages:
+// ClientAuthorizeLocalDeviceRequest (client->server): Request token for local device.
+// ClientAuthorizeLocalDevice (server->client): Authorization result with device token.
+// ClientDeauthorizeDeviceRequest (client->server): Revoke authorization of device.
+// ClientDeauthorizeDevice (server->client): Deauthorization result.
+// ClientUseLocalDeviceAuthorizations (client->server): Announce tokens used on local device.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package deviceauth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// OwnDevice is a device authorized to use library of account.
+type OwnDevice struct {
+	Token          uint64
+	Name           string
+	IsPending      bool
+	IsCanceled     bool
+	IsLimited      bool
+	LastTimeUsed   time.Time
+	LastBorrowerID uint64
+	LastAppPlayed  uint32
+}
+
+// UsedDevice is a device of account, which is authorized to use library of other account.
+type UsedDevice struct {
+	Token         uint64
+	Name          string
+	OwnerSteamID  uint64
+	LastTimeUsed  time.Time
+	LastAppPlayed uint32
+}
+
+// Borrower is an account authorized to use library.
+type Borrower struct {
+	SteamID     uint64
+	IsPending   bool
+	IsCanceled  bool
+	TimeCreated time.Time
+}
+
+// DeviceToken is a token of device authorized by owner of library.
+type DeviceToken struct {
+	OwnerAccountID uint32
+	Token          uint64
+}
+
+// Module used to manage authorized devices and borrowers.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+}
+
+// NewModule initialize new instance of deviceauth Module.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientSharedLibraryLockStatus:
+		return m.handleLockStatus(p)
+	case steamprotocol.EMsg_ClientSharedLibraryStopPlaying:
+		return m.handleStopPlaying(p)
+	}
+
+	return nil
+}
+
+// OwnAuthorizedDevices return devices authorized to use library of current account.
+func (m *Module) OwnAuthorizedDevices(ctx context.Context, includeCanceled bool) ([]OwnDevice, error) {
+	var resp unified.CDeviceAuth_GetOwnAuthorizedDevices_Response
+
+	err := m.cl.CallService(ctx, "DeviceAuth.GetOwnAuthorizedDevices#1", &unified.CDeviceAuth_GetOwnAuthorizedDevices_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		IncludeCanceled: proto.Bool(includeCanceled),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]OwnDevice, 0, len(resp.GetDevices()))
+	for _, d := range resp.GetDevices() {
+		devices = append(devices, OwnDevice{
+			Token:          d.GetAuthDeviceToken(),
+			Name:           d.GetDeviceName(),
+			IsPending:      d.GetIsPending(),
+			IsCanceled:     d.GetIsCanceled(),
+			IsLimited:      d.GetIsLimited(),
+			LastTimeUsed:   steamprotocol.UnixTime(d.GetLastTimeUsed()),
+			LastBorrowerID: d.GetLastBorrowerId(),
+			LastAppPlayed:  d.GetLastAppPlayed(),
+		})
+	}
+
+	return devices, nil
+}
+
+// UsedAuthorizedDevices return devices of current account,
+// which are authorized to use libraries of other accounts.
+func (m *Module) UsedAuthorizedDevices(ctx context.Context) ([]UsedDevice, error) {
+	var resp unified.CDeviceAuth_GetUsedAuthorizedDevices_Response
+
+	err := m.cl.CallService(ctx, "DeviceAuth.GetUsedAuthorizedDevices#1", &unified.CDeviceAuth_GetUsedAuthorizedDevices_Request{
+		Steamid: proto.Uint64(m.cl.Session().SteamID),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]UsedDevice, 0, len(resp.GetDevices()))
+	for _, d := range resp.GetDevices() {
+		devices = append(devices, UsedDevice{
+			Token:         d.GetAuthDeviceToken(),
+			Name:          d.GetDeviceName(),
+			OwnerSteamID:  d.GetOwnerSteamid(),
+			LastTimeUsed:  steamprotocol.UnixTime(d.GetLastTimeUsed()),
+			LastAppPlayed: d.GetLastAppPlayed(),
+		})
+	}
+
+	return devices, nil
+}
+
+// AcceptAuthorizationRequest accept request of borrower fromSteamID to authorize device.
+// Token and code are taken from authorization request email.
+func (m *Module) AcceptAuthorizationRequest(ctx context.Context, fromSteamID, token, code uint64) error {
+	var resp unified.CDeviceAuth_AcceptAuthorizationRequest_Response
+
+	return m.cl.CallService(ctx, "DeviceAuth.AcceptAuthorizationRequest#1", &unified.CDeviceAuth_AcceptAuthorizationRequest_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		AuthDeviceToken: proto.Uint64(token),
+		AuthCode:        proto.Uint64(code),
+		FromSteamid:     proto.Uint64(fromSteamID),
+	}, &resp)
+}
+
+// AuthorizeRemoteDevice authorize device to use library of current account.
+func (m *Module) AuthorizeRemoteDevice(ctx context.Context, token uint64) error {
+	var resp unified.CDeviceAuth_AuthorizeRemoteDevice_Response
+
+	return m.cl.CallService(ctx, "DeviceAuth.AuthorizeRemoteDevice#1", &unified.CDeviceAuth_AuthorizeRemoteDevice_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		AuthDeviceToken: proto.Uint64(token),
+	}, &resp)
+}
+
+// DeauthorizeRemoteDevice revoke authorization of device to use library of current account.
+func (m *Module) DeauthorizeRemoteDevice(ctx context.Context, token uint64) error {
+	var resp unified.CDeviceAuth_DeauthorizeRemoteDevice_Response
+
+	return m.cl.CallService(ctx, "DeviceAuth.DeauthorizeRemoteDevice#1", &unified.CDeviceAuth_DeauthorizeRemoteDevice_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		AuthDeviceToken: proto.Uint64(token),
+	}, &resp)
+}
+
+// AuthorizedBorrowers return accounts authorized to use library of current account.
+func (m *Module) AuthorizedBorrowers(ctx context.Context, includeCanceled, includePending bool) ([]Borrower, error) {
+	var resp unified.CDeviceAuth_GetAuthorizedBorrowers_Response
+
+	err := m.cl.CallService(ctx, "DeviceAuth.GetAuthorizedBorrowers#1", &unified.CDeviceAuth_GetAuthorizedBorrowers_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		IncludeCanceled: proto.Bool(includeCanceled),
+		IncludePending:  proto.Bool(includePending),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	borrowers := make([]Borrower, 0, len(resp.GetBorrowers()))
+	for _, b := range resp.GetBorrowers() {
+		borrowers = append(borrowers, Borrower{
+			SteamID:     b.GetSteamid(),
+			IsPending:   b.GetIsPending(),
+			IsCanceled:  b.GetIsCanceled(),
+			TimeCreated: steamprotocol.UnixTime(b.GetTimeCreated()),
+		})
+	}
+
+	return borrowers, nil
+}
+
+// AddAuthorizedBorrowers authorize accounts to use library of current account.
+// Steam limits how often borrowers can be added, so returned duration
+// must be waited before next call.
+func (m *Module) AddAuthorizedBorrowers(ctx context.Context, steamIDs ...uint64) (time.Duration, error) {
+	var resp unified.CDeviceAuth_AddAuthorizedBorrowers_Response
+
+	err := m.cl.CallService(ctx, "DeviceAuth.AddAuthorizedBorrowers#1", &unified.CDeviceAuth_AddAuthorizedBorrowers_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		SteamidBorrower: steamIDs,
+	}, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(resp.GetSecondsToWait()) * time.Second, nil
+}
+
+// RemoveAuthorizedBorrowers revoke authorization of accounts to use library of current account.
+func (m *Module) RemoveAuthorizedBorrowers(ctx context.Context, steamIDs ...uint64) error {
+	var resp unified.CDeviceAuth_RemoveAuthorizedBorrowers_Response
+
+	return m.cl.CallService(ctx, "DeviceAuth.RemoveAuthorizedBorrowers#1", &unified.CDeviceAuth_RemoveAuthorizedBorrowers_Request{
+		Steamid:         proto.Uint64(m.cl.Session().SteamID),
+		SteamidBorrower: steamIDs,
+	}, &resp)
+}
+
+// AuthorizeLocalDevice request authorization of local device to use library of owner.
+// Owner receives email with authorization request, and token of device is returned.
+func (m *Module) AuthorizeLocalDevice(ctx context.Context, description string, ownerAccountID uint32) (uint64, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientAuthorizeLocalDeviceRequest, &protobuf.CMsgClientAuthorizeLocalDeviceRequest{
+		DeviceDescription: proto.String(description),
+		OwnerAccountId:    proto.Uint32(ownerAccountID),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to request local device authorization")
+	}
+
+	var msg protobuf.CMsgClientAuthorizeLocalDevice
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read local device authorization")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return 0, fmt.Errorf("local device authorization failed with result %s", result.String())
+	}
+
+	return msg.GetAuthedDeviceToken(), nil
+}
+
+// DeauthorizeDevice revoke authorization of device for account.
+func (m *Module) DeauthorizeDevice(ctx context.Context, accountID uint32, token uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientDeauthorizeDeviceRequest, &protobuf.CMsgClientDeauthorizeDeviceRequest{
+		DeauthorizationAccountId:   proto.Uint32(accountID),
+		DeauthorizationDeviceToken: proto.Uint64(token),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to request device deauthorization")
+	}
+
+	var msg protobuf.CMsgClientDeauthorizeDevice
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read device deauthorization")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("device deauthorization failed with result %s", result.String())
+	}
+
+	return nil
+}
+
+// UseLocalDeviceAuthorizations tell Steam which authorizations are stored on local device,
+// so libraries of owners become available to current account.
+func (m *Module) UseLocalDeviceAuthorizations(tokens ...DeviceToken) error {
+	msg := &protobuf.CMsgClientUseLocalDeviceAuthorizations{}
+
+	for _, t := range tokens {
+		msg.AuthorizationAccountId = append(msg.AuthorizationAccountId, t.OwnerAccountID)
+		msg.DeviceTokens = append(msg.DeviceTokens, &protobuf.CMsgClientUseLocalDeviceAuthorizations_DeviceToken{
+			OwnerAccountId: proto.Uint32(t.OwnerAccountID),
+			TokenId:        proto.Uint64(t.Token),
+		})
+	}
+
+	return m.cl.Send(steamprotocol.EMsg_ClientUseLocalDeviceAuthorizations, msg)
+}
+
+func (m *Module) handleLockStatus(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientSharedLibraryLockStatus
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read shared library lock status")
+	}
+
+	event := SharedLibraryLockStatusEvent{
+		OwnLibraryLockedBy: msg.GetOwnLibraryLockedBy(),
+	}
+
+	for _, l := range msg.GetLockedLibrary() {
+		event.LockedLibraries = append(event.LockedLibraries, LockedLibrary{
+			OwnerID:  l.GetOwnerId(),
+			LockedBy: l.GetLockedBy(),
+		})
+	}
+
+	return m.eventManager.FireEvent(event)
+}
+
+func (m *Module) handleStopPlaying(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientSharedLibraryStopPlaying
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read shared library stop playing")
+	}
+
+	event := SharedLibraryStopPlayingEvent{
+		SecondsLeft: msg.GetSecondsLeft(),
+	}
+
+	for _, a := range msg.GetStopApps() {
+		event.Apps = append(event.Apps, StopApp{
+			AppID:   a.GetAppId(),
+			OwnerID: a.GetOwnerId(),
+		})
+	}
+
+	return m.eventManager.FireEvent(event)
+}
@@ -0,0 +1,151 @@
+package steamprotocol
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EPurchaseResultDetail is absent in generated enums, so it's declared separately in the same format.
+type EPurchaseResultDetail int32
+
+const (
+	EPurchaseResultDetail_NoDetail                                EPurchaseResultDetail = 0
+	EPurchaseResultDetail_AVSFailure                              EPurchaseResultDetail = 1
+	EPurchaseResultDetail_InsufficientFunds                       EPurchaseResultDetail = 2
+	EPurchaseResultDetail_ContactSupport                          EPurchaseResultDetail = 3
+	EPurchaseResultDetail_Timeout                                 EPurchaseResultDetail = 4
+	EPurchaseResultDetail_InvalidPackage                          EPurchaseResultDetail = 5
+	EPurchaseResultDetail_InvalidPaymentMethod                    EPurchaseResultDetail = 6
+	EPurchaseResultDetail_InvalidData                             EPurchaseResultDetail = 7
+	EPurchaseResultDetail_OthersInProgress                        EPurchaseResultDetail = 8
+	EPurchaseResultDetail_AlreadyPurchased                        EPurchaseResultDetail = 9
+	EPurchaseResultDetail_WrongPrice                              EPurchaseResultDetail = 10
+	EPurchaseResultDetail_FraudCheckFailed                        EPurchaseResultDetail = 11
+	EPurchaseResultDetail_CancelledByUser                         EPurchaseResultDetail = 12
+	EPurchaseResultDetail_RestrictedCountry                       EPurchaseResultDetail = 13
+	EPurchaseResultDetail_BadActivationCode                       EPurchaseResultDetail = 14
+	EPurchaseResultDetail_DuplicateActivationCode                 EPurchaseResultDetail = 15
+	EPurchaseResultDetail_UseOtherPaymentMethod                   EPurchaseResultDetail = 16
+	EPurchaseResultDetail_UseOtherFunctionSource                  EPurchaseResultDetail = 17
+	EPurchaseResultDetail_InvalidShippingAddress                  EPurchaseResultDetail = 18
+	EPurchaseResultDetail_RegionNotSupported                      EPurchaseResultDetail = 19
+	EPurchaseResultDetail_AcctIsBlocked                           EPurchaseResultDetail = 20
+	EPurchaseResultDetail_AcctNotVerified                         EPurchaseResultDetail = 21
+	EPurchaseResultDetail_InvalidAccount                          EPurchaseResultDetail = 22
+	EPurchaseResultDetail_StoreBillingCountryMismatch             EPurchaseResultDetail = 23
+	EPurchaseResultDetail_DoesNotOwnRequiredApp                   EPurchaseResultDetail = 24
+	EPurchaseResultDetail_CanceledByNewTransaction                EPurchaseResultDetail = 25
+	EPurchaseResultDetail_ForceCanceledPending                    EPurchaseResultDetail = 26
+	EPurchaseResultDetail_FailCurrencyTransProvider               EPurchaseResultDetail = 27
+	EPurchaseResultDetail_FailedCyberCafe                         EPurchaseResultDetail = 28
+	EPurchaseResultDetail_NeedsPreApproval                        EPurchaseResultDetail = 29
+	EPurchaseResultDetail_PreApprovalDenied                       EPurchaseResultDetail = 30
+	EPurchaseResultDetail_WalletCurrencyMismatch                  EPurchaseResultDetail = 31
+	EPurchaseResultDetail_EmailNotValidated                       EPurchaseResultDetail = 32
+	EPurchaseResultDetail_ExpiredCard                             EPurchaseResultDetail = 33
+	EPurchaseResultDetail_TransactionExpired                      EPurchaseResultDetail = 34
+	EPurchaseResultDetail_WouldExceedMaxWallet                    EPurchaseResultDetail = 35
+	EPurchaseResultDetail_MustLoginPS3AppForPurchase              EPurchaseResultDetail = 36
+	EPurchaseResultDetail_CannotShipToPOBox                       EPurchaseResultDetail = 37
+	EPurchaseResultDetail_InsufficientInventory                   EPurchaseResultDetail = 38
+	EPurchaseResultDetail_CannotGiftShippedGoods                  EPurchaseResultDetail = 39
+	EPurchaseResultDetail_CannotShipInternationally               EPurchaseResultDetail = 40
+	EPurchaseResultDetail_BillingAgreementCancelled               EPurchaseResultDetail = 41
+	EPurchaseResultDetail_InvalidCoupon                           EPurchaseResultDetail = 42
+	EPurchaseResultDetail_ExpiredCoupon                           EPurchaseResultDetail = 43
+	EPurchaseResultDetail_AccountLocked                           EPurchaseResultDetail = 44
+	EPurchaseResultDetail_OtherAbortableInProgress                EPurchaseResultDetail = 45
+	EPurchaseResultDetail_ExceededSteamLimit                      EPurchaseResultDetail = 46
+	EPurchaseResultDetail_OverlappingPackagesInCart               EPurchaseResultDetail = 47
+	EPurchaseResultDetail_NoWallet                                EPurchaseResultDetail = 48
+	EPurchaseResultDetail_NoCachedPaymentMethod                   EPurchaseResultDetail = 49
+	EPurchaseResultDetail_CannotRedeemCodeFromClient              EPurchaseResultDetail = 50
+	EPurchaseResultDetail_PurchaseAmountNoSupportedByProvider     EPurchaseResultDetail = 51
+	EPurchaseResultDetail_OverlappingPackagesInPendingTransaction EPurchaseResultDetail = 52
+	EPurchaseResultDetail_RateLimited                             EPurchaseResultDetail = 53
+	EPurchaseResultDetail_OwnsExcludedApp                         EPurchaseResultDetail = 54
+	EPurchaseResultDetail_CreditCardBinMismatchesType             EPurchaseResultDetail = 55
+	EPurchaseResultDetail_CartValueTooHigh                        EPurchaseResultDetail = 56
+	EPurchaseResultDetail_BillingAgreementAlreadyExists           EPurchaseResultDetail = 57
+	EPurchaseResultDetail_POSACodeNotActivated                    EPurchaseResultDetail = 58
+)
+
+var EPurchaseResultDetail_name = map[EPurchaseResultDetail]string{
+	0:  "EPurchaseResultDetail_NoDetail",
+	1:  "EPurchaseResultDetail_AVSFailure",
+	2:  "EPurchaseResultDetail_InsufficientFunds",
+	3:  "EPurchaseResultDetail_ContactSupport",
+	4:  "EPurchaseResultDetail_Timeout",
+	5:  "EPurchaseResultDetail_InvalidPackage",
+	6:  "EPurchaseResultDetail_InvalidPaymentMethod",
+	7:  "EPurchaseResultDetail_InvalidData",
+	8:  "EPurchaseResultDetail_OthersInProgress",
+	9:  "EPurchaseResultDetail_AlreadyPurchased",
+	10: "EPurchaseResultDetail_WrongPrice",
+	11: "EPurchaseResultDetail_FraudCheckFailed",
+	12: "EPurchaseResultDetail_CancelledByUser",
+	13: "EPurchaseResultDetail_RestrictedCountry",
+	14: "EPurchaseResultDetail_BadActivationCode",
+	15: "EPurchaseResultDetail_DuplicateActivationCode",
+	16: "EPurchaseResultDetail_UseOtherPaymentMethod",
+	17: "EPurchaseResultDetail_UseOtherFunctionSource",
+	18: "EPurchaseResultDetail_InvalidShippingAddress",
+	19: "EPurchaseResultDetail_RegionNotSupported",
+	20: "EPurchaseResultDetail_AcctIsBlocked",
+	21: "EPurchaseResultDetail_AcctNotVerified",
+	22: "EPurchaseResultDetail_InvalidAccount",
+	23: "EPurchaseResultDetail_StoreBillingCountryMismatch",
+	24: "EPurchaseResultDetail_DoesNotOwnRequiredApp",
+	25: "EPurchaseResultDetail_CanceledByNewTransaction",
+	26: "EPurchaseResultDetail_ForceCanceledPending",
+	27: "EPurchaseResultDetail_FailCurrencyTransProvider",
+	28: "EPurchaseResultDetail_FailedCyberCafe",
+	29: "EPurchaseResultDetail_NeedsPreApproval",
+	30: "EPurchaseResultDetail_PreApprovalDenied",
+	31: "EPurchaseResultDetail_WalletCurrencyMismatch",
+	32: "EPurchaseResultDetail_EmailNotValidated",
+	33: "EPurchaseResultDetail_ExpiredCard",
+	34: "EPurchaseResultDetail_TransactionExpired",
+	35: "EPurchaseResultDetail_WouldExceedMaxWallet",
+	36: "EPurchaseResultDetail_MustLoginPS3AppForPurchase",
+	37: "EPurchaseResultDetail_CannotShipToPOBox",
+	38: "EPurchaseResultDetail_InsufficientInventory",
+	39: "EPurchaseResultDetail_CannotGiftShippedGoods",
+	40: "EPurchaseResultDetail_CannotShipInternationally",
+	41: "EPurchaseResultDetail_BillingAgreementCancelled",
+	42: "EPurchaseResultDetail_InvalidCoupon",
+	43: "EPurchaseResultDetail_ExpiredCoupon",
+	44: "EPurchaseResultDetail_AccountLocked",
+	45: "EPurchaseResultDetail_OtherAbortableInProgress",
+	46: "EPurchaseResultDetail_ExceededSteamLimit",
+	47: "EPurchaseResultDetail_OverlappingPackagesInCart",
+	48: "EPurchaseResultDetail_NoWallet",
+	49: "EPurchaseResultDetail_NoCachedPaymentMethod",
+	50: "EPurchaseResultDetail_CannotRedeemCodeFromClient",
+	51: "EPurchaseResultDetail_PurchaseAmountNoSupportedByProvider",
+	52: "EPurchaseResultDetail_OverlappingPackagesInPendingTransaction",
+	53: "EPurchaseResultDetail_RateLimited",
+	54: "EPurchaseResultDetail_OwnsExcludedApp",
+	55: "EPurchaseResultDetail_CreditCardBinMismatchesType",
+	56: "EPurchaseResultDetail_CartValueTooHigh",
+	57: "EPurchaseResultDetail_BillingAgreementAlreadyExists",
+	58: "EPurchaseResultDetail_POSACodeNotActivated",
+}
+
+func (e EPurchaseResultDetail) String() string {
+	if s, ok := EPurchaseResultDetail_name[e]; ok {
+		return s
+	}
+	var flags []string
+	for k, v := range EPurchaseResultDetail_name {
+		if e&k != 0 {
+			flags = append(flags, v)
+		}
+	}
+	if len(flags) == 0 {
+		return fmt.Sprintf("%d", e)
+	}
+	sort.Strings(flags)
+	return strings.Join(flags, " | ")
+}
@@ -0,0 +1,17 @@
+package gamenotifications
+
+// NotificationsRequestedEvent is fired when GameNotificationsClient.OnNotificationsRequested is received.
+// Game should update sessions of user, so Steam can show actual notifications.
+type NotificationsRequestedEvent struct {
+	SteamID uint64
+	AppID   uint32
+}
+
+// UserStatusChangedEvent is fired when GameNotificationsClient.OnUserStatusChanged is received.
+type UserStatusChangedEvent struct {
+	SteamID   uint64
+	SessionID uint64
+	AppID     uint32
+	Status    UserStatus
+	Removed   bool
+}
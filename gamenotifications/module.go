@@ -0,0 +1,299 @@
+// Package gamenotifications used to manage turn notification sessions of async games
+// with GameNotifications unified service.
+//
+// Game creates session for a match, and updates status of every user in it.
+// Steam shows notification to user, which state requires action.
+// Steam notifies game with GameNotificationsClient methods, when user
+// requests notifications or status of user is changed.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package gamenotifications
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// LocalizedText is a localization token of game, which is rendered with variables.
+type LocalizedText struct {
+	Token     string
+	Variables map[string]string
+	// RenderedText is set by Steam in responses.
+	RenderedText string
+}
+
+// UserStatus is a state of user in session.
+// State is defined by game, like "waiting" or "ready_to_play".
+type UserStatus struct {
+	SteamID uint64
+	State   string
+	Title   LocalizedText
+	Message LocalizedText
+}
+
+// Session is a notification session of game match.
+type Session struct {
+	ID          uint64
+	AppID       uint32
+	Context     uint64
+	Title       LocalizedText
+	TimeCreated time.Time
+	TimeUpdated time.Time
+	Users       []UserStatus
+}
+
+// Module used to manage game notification sessions.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+}
+
+// NewModule initialize new instance of gamenotifications Module.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ServiceMethod, steamprotocol.EMsg_ClientServiceMethod:
+		return m.handleServiceMethod(p)
+	}
+
+	return nil
+}
+
+// CreateSession create notification session and return its id.
+// matchContext is an id of match defined by game.
+func (m *Module) CreateSession(ctx context.Context, appID uint32, matchContext uint64, title LocalizedText, users []UserStatus) (uint64, error) {
+	var resp unified.CGameNotifications_CreateSession_Response
+
+	err := m.cl.CallService(ctx, "GameNotifications.UserCreateSession#1", &unified.CGameNotifications_CreateSession_Request{
+		Appid:   proto.Uint32(appID),
+		Context: proto.Uint64(matchContext),
+		Title:   title.proto(),
+		Users:   protoUsers(users),
+		Steamid: proto.Uint64(m.cl.Session().SteamID),
+	}, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.GetSessionid(), nil
+}
+
+// UpdateSession change title and user statuses of session.
+func (m *Module) UpdateSession(ctx context.Context, appID uint32, sessionID uint64, title LocalizedText, users []UserStatus) error {
+	var resp unified.CGameNotifications_UpdateSession_Response
+
+	return m.cl.CallService(ctx, "GameNotifications.UserUpdateSession#1", &unified.CGameNotifications_UpdateSession_Request{
+		Sessionid: proto.Uint64(sessionID),
+		Appid:     proto.Uint32(appID),
+		Title:     title.proto(),
+		Users:     protoUsers(users),
+		Steamid:   proto.Uint64(m.cl.Session().SteamID),
+	}, &resp)
+}
+
+// DeleteSession delete session, when match is over.
+func (m *Module) DeleteSession(ctx context.Context, appID uint32, sessionID uint64) error {
+	var resp unified.CGameNotifications_DeleteSession_Response
+
+	return m.cl.CallService(ctx, "GameNotifications.UserDeleteSession#1", &unified.CGameNotifications_DeleteSession_Request{
+		Sessionid: proto.Uint64(sessionID),
+		Appid:     proto.Uint32(appID),
+		Steamid:   proto.Uint64(m.cl.Session().SteamID),
+	}, &resp)
+}
+
+// EnumerateSessions return sessions of app, which current user participates in.
+// Texts are rendered in language, like "english".
+func (m *Module) EnumerateSessions(ctx context.Context, appID uint32, language string) ([]Session, error) {
+	var resp unified.CGameNotifications_EnumerateSessions_Response
+
+	err := m.cl.CallService(ctx, "GameNotifications.EnumerateSessions#1", &unified.CGameNotifications_EnumerateSessions_Request{
+		Appid:                  proto.Uint32(appID),
+		IncludeAllUserMessages: proto.Bool(true),
+		Language:               proto.String(language),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSessions(resp.GetSessions()), nil
+}
+
+// GetSessionDetails return sessions of app by ids.
+// Texts are rendered in language, like "english".
+func (m *Module) GetSessionDetails(ctx context.Context, appID uint32, language string, sessionIDs ...uint64) ([]Session, error) {
+	req := &unified.CGameNotifications_GetSessionDetails_Request{
+		Appid:    proto.Uint32(appID),
+		Language: proto.String(language),
+	}
+
+	for _, id := range sessionIDs {
+		req.Sessions = append(req.Sessions, &unified.CGameNotifications_GetSessionDetails_Request_RequestedSession{
+			Sessionid:              proto.Uint64(id),
+			IncludeAuthUserMessage: proto.Bool(true),
+		})
+	}
+
+	var resp unified.CGameNotifications_GetSessionDetails_Response
+
+	err := m.cl.CallService(ctx, "GameNotifications.GetSessionDetails#1", req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSessions(resp.GetSessions()), nil
+}
+
+// UpdateNotificationSettings allow or disallow notifications of apps for current user.
+func (m *Module) UpdateNotificationSettings(ctx context.Context, allowed map[uint32]bool) error {
+	req := &unified.CGameNotifications_UpdateNotificationSettings_Request{}
+
+	for appID, allow := range allowed {
+		req.GameNotificationSettings = append(req.GameNotificationSettings, &unified.GameNotificationSettings{
+			Appid:              proto.Uint32(appID),
+			AllowNotifications: proto.Bool(allow),
+		})
+	}
+
+	var resp unified.CGameNotifications_UpdateNotificationSettings_Response
+
+	return m.cl.CallService(ctx, "GameNotifications.UpdateNotificationSettings#1", req, &resp)
+}
+
+func (m *Module) handleServiceMethod(p *steamprotocol.Packet) error {
+	method, err := steamprotocol.ReadServiceMethod(p)
+	if err != nil {
+		return err
+	}
+
+	switch method.Name {
+	case "GameNotificationsClient.OnNotificationsRequested#1":
+		var msg unified.CGameNotifications_OnNotificationsRequested_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal notifications requested")
+		}
+
+		return m.eventManager.FireEvent(NotificationsRequestedEvent{
+			SteamID: msg.GetSteamid(),
+			AppID:   msg.GetAppid(),
+		})
+	case "GameNotificationsClient.OnUserStatusChanged#1":
+		var msg unified.CGameNotifications_OnUserStatusChanged_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal user status changed")
+		}
+
+		return m.eventManager.FireEvent(UserStatusChangedEvent{
+			SteamID:   msg.GetSteamid(),
+			SessionID: msg.GetSessionid(),
+			AppID:     msg.GetAppid(),
+			Status:    newUserStatus(msg.GetStatus()),
+			Removed:   msg.GetRemoved(),
+		})
+	}
+
+	return nil
+}
+
+func (t LocalizedText) proto() *unified.CGameNotifications_LocalizedText {
+	keys := make([]string, 0, len(t.Variables))
+	for k := range t.Variables {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	text := &unified.CGameNotifications_LocalizedText{
+		Token: proto.String(t.Token),
+	}
+
+	for _, k := range keys {
+		text.Variables = append(text.Variables, &unified.CGameNotifications_Variable{
+			Key:   proto.String(k),
+			Value: proto.String(t.Variables[k]),
+		})
+	}
+
+	return text
+}
+
+func newLocalizedText(t *unified.CGameNotifications_LocalizedText) LocalizedText {
+	text := LocalizedText{
+		Token:        t.GetToken(),
+		Variables:    make(map[string]string, len(t.GetVariables())),
+		RenderedText: t.GetRenderedText(),
+	}
+
+	for _, v := range t.GetVariables() {
+		text.Variables[v.GetKey()] = v.GetValue()
+	}
+
+	return text
+}
+
+func protoUsers(users []UserStatus) []*unified.CGameNotifications_UserStatus {
+	result := make([]*unified.CGameNotifications_UserStatus, 0, len(users))
+	for _, u := range users {
+		result = append(result, &unified.CGameNotifications_UserStatus{
+			Steamid: proto.Uint64(u.SteamID),
+			State:   proto.String(u.State),
+			Title:   u.Title.proto(),
+			Message: u.Message.proto(),
+		})
+	}
+
+	return result
+}
+
+func newUserStatus(u *unified.CGameNotifications_UserStatus) UserStatus {
+	return UserStatus{
+		SteamID: u.GetSteamid(),
+		State:   u.GetState(),
+		Title:   newLocalizedText(u.GetTitle()),
+		Message: newLocalizedText(u.GetMessage()),
+	}
+}
+
+func newSessions(sessions []*unified.CGameNotifications_Session) []Session {
+	result := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		session := Session{
+			ID:          s.GetSessionid(),
+			AppID:       uint32(s.GetAppid()),
+			Context:     s.GetContext(),
+			Title:       newLocalizedText(s.GetTitle()),
+			TimeCreated: time.Unix(int64(s.GetTimeCreated()), 0),
+			TimeUpdated: time.Unix(int64(s.GetTimeUpdated()), 0),
+		}
+
+		for _, u := range s.GetUserStatus() {
+			session.Users = append(session.Users, newUserStatus(u))
+		}
+
+		result = append(result, session)
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package gameserver
+
+import "github.com/furdarius/steamprotocol"
+
+// StatusReplyEvent is fired when CMsgGSStatusReply is received.
+type StatusReplyEvent struct {
+	IsSecure bool
+}
+
+// ApproveEvent is fired when CMsgGSApprove is received.
+// OwnerSteamID differs from SteamID, if user plays borrowed app.
+type ApproveEvent struct {
+	SteamID      uint64
+	OwnerSteamID uint64
+}
+
+// DenyEvent is fired when CMsgGSDeny is received.
+// Player must be disconnected by server with DisconnectPlayer.
+type DenyEvent struct {
+	SteamID uint64
+	Reason  steamprotocol.EDenyReason
+	Message string
+}
+
+// KickEvent is fired when CMsgGSKick is received.
+// Player must be disconnected by server with DisconnectPlayer.
+type KickEvent struct {
+	SteamID uint64
+	Reason  steamprotocol.EDenyReason
+}
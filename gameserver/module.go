@@ -0,0 +1,356 @@
+// Package gameserver used to run game server without Steamworks SDK.
+//
+// Server logs on with auth module, and reports its type with GSServerType.
+// Tickets of connected players are activated with ClientAuthList,
+// and Steam responds with GSApprove or GSDeny.
+// Steam may kick approved player later with GSKick.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package gameserver
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"net"
+	"sync"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/authtickets"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ErrServerTypeNotSet is returned when players are validated before SetServerType call.
+var ErrServerTypeNotSet = errors.New("server type is not set")
+
+// ServerType describes game server.
+type ServerType struct {
+	AppID     uint32
+	Flags     steamprotocol.EServerFlags
+	Address   net.IP
+	Port      uint16
+	QueryPort uint16
+	GameDir   string
+	Version   string
+}
+
+// Player is a user connected to game server.
+type Player struct {
+	SteamID      uint64
+	OwnerSteamID uint64
+	PublicIP     net.IP
+	// Approved is set, when Steam approves ticket of player.
+	Approved bool
+
+	ticketCRC uint32
+	token     []byte
+}
+
+// Module used to manage game server.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	authTickets  *authtickets.Module
+
+	mu       sync.RWMutex
+	appID    uint32
+	isSecure bool
+	players  map[uint64]*Player
+}
+
+// NewModule initialize new instance of gameserver Module.
+// authTickets is used to activate tickets of players.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager, authTickets *authtickets.Module) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		authTickets:  authTickets,
+		players:      make(map[uint64]*Player),
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_GSStatusReply:
+		return m.handleStatusReply(p)
+	case steamprotocol.EMsg_GSApprove:
+		return m.handleApprove(p)
+	case steamprotocol.EMsg_GSDeny:
+		return m.handleDeny(p)
+	case steamprotocol.EMsg_GSKick:
+		return m.handleKick(p)
+	}
+
+	return nil
+}
+
+// SetServerType report type and address of server to Steam.
+// It must be called after logon, before players are validated.
+func (m *Module) SetServerType(t ServerType) error {
+	m.mu.Lock()
+	m.appID = t.AppID
+	m.mu.Unlock()
+
+	return m.cl.Send(steamprotocol.EMsg_GSServerType, &protobuf.CMsgGSServerType{
+		AppIdServed:   proto.Uint32(t.AppID),
+		Flags:         proto.Uint32(uint32(t.Flags)),
+		GameIpAddress: proto.Uint32(ipToUint32(t.Address)),
+		GamePort:      proto.Uint32(uint32(t.Port)),
+		GameDir:       proto.String(t.GameDir),
+		GameVersion:   proto.String(t.Version),
+		GameQueryPort: proto.Uint32(uint32(t.QueryPort)),
+	})
+}
+
+// IsSecure reports whether Steam marked server as VAC secured.
+func (m *Module) IsSecure() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.isSecure
+}
+
+// Players return players connected to server.
+func (m *Module) Players() []Player {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	players := make([]Player, 0, len(m.players))
+	for _, p := range m.players {
+		players = append(players, *p)
+	}
+
+	return players
+}
+
+// ValidatePlayer add player to player list, and activate session auth ticket of player.
+// Result of validation is fired as ApproveEvent or DenyEvent.
+func (m *Module) ValidatePlayer(ctx context.Context, steamID uint64, publicIP net.IP, ticket []byte) error {
+	m.mu.RLock()
+	appID := m.appID
+	m.mu.RUnlock()
+
+	if appID == 0 {
+		return ErrServerTypeNotSet
+	}
+
+	token, err := readToken(ticket)
+	if err != nil {
+		return err
+	}
+
+	err = m.authTickets.ActivateAuthSessionTicket(ctx, appID, steamID, ticket)
+	if err != nil {
+		return errors.Wrap(err, "failed to activate ticket")
+	}
+
+	m.mu.Lock()
+	m.players[steamID] = &Player{
+		SteamID:   steamID,
+		PublicIP:  publicIP,
+		ticketCRC: crc32.ChecksumIEEE(ticket),
+		token:     token,
+	}
+	m.mu.Unlock()
+
+	err = m.cl.Send(steamprotocol.EMsg_GSUserPlaying, &protobuf.CMsgGSUserPlaying{
+		SteamId:  proto.Uint64(steamID),
+		PublicIp: proto.Uint32(ipToUint32(publicIP)),
+		Token:    token,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to send user playing")
+	}
+
+	return m.sendPlayerList()
+}
+
+// DisconnectPlayer remove player from player list, and cancel ticket of player.
+func (m *Module) DisconnectPlayer(ctx context.Context, steamID uint64) error {
+	player, ok := m.removePlayer(steamID)
+	if !ok {
+		return fmt.Errorf("player %d is not connected", steamID)
+	}
+
+	err := m.cl.Send(steamprotocol.EMsg_GSDisconnectNotice, &protobuf.CMsgGSDisconnectNotice{
+		SteamId: proto.Uint64(steamID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to send disconnect notice")
+	}
+
+	err = m.authTickets.CancelAuthTicket(ctx, player.ticketCRC)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel ticket")
+	}
+
+	return m.sendPlayerList()
+}
+
+// AssociateWithClan associate server with Steam group.
+func (m *Module) AssociateWithClan(ctx context.Context, clanID uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_GSAssociateWithClan, &protobuf.CMsgGSAssociateWithClan{
+		SteamIdClan: proto.Uint64(clanID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to associate with clan")
+	}
+
+	var msg protobuf.CMsgGSAssociateWithClanResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read associate with clan response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("clan association failed with result %s", result.String())
+	}
+
+	return nil
+}
+
+func (m *Module) sendPlayerList() error {
+	m.mu.RLock()
+
+	msg := &protobuf.CMsgGSPlayerList{}
+	for _, p := range m.players {
+		msg.Players = append(msg.Players, &protobuf.CMsgGSPlayerList_Player{
+			SteamId:  proto.Uint64(p.SteamID),
+			PublicIp: proto.Uint32(ipToUint32(p.PublicIP)),
+			Token:    p.token,
+		})
+	}
+
+	m.mu.RUnlock()
+
+	return m.cl.Send(steamprotocol.EMsg_GSPlayerList, msg)
+}
+
+func (m *Module) removePlayer(steamID uint64) (*Player, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	player, ok := m.players[steamID]
+	if ok {
+		delete(m.players, steamID)
+	}
+
+	return player, ok
+}
+
+func (m *Module) disapprove(steamID uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if player, ok := m.players[steamID]; ok {
+		player.Approved = false
+	}
+}
+
+func (m *Module) handleStatusReply(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgGSStatusReply
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read status reply")
+	}
+
+	m.mu.Lock()
+	m.isSecure = msg.GetIsSecure()
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(StatusReplyEvent{
+		IsSecure: msg.GetIsSecure(),
+	})
+}
+
+func (m *Module) handleApprove(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgGSApprove
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read approve")
+	}
+
+	m.mu.Lock()
+	if player, ok := m.players[msg.GetSteamId()]; ok {
+		player.Approved = true
+		player.OwnerSteamID = msg.GetOwnerSteamId()
+	}
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(ApproveEvent{
+		SteamID:      msg.GetSteamId(),
+		OwnerSteamID: msg.GetOwnerSteamId(),
+	})
+}
+
+func (m *Module) handleDeny(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgGSDeny
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read deny")
+	}
+
+	m.disapprove(msg.GetSteamId())
+
+	return m.eventManager.FireEvent(DenyEvent{
+		SteamID: msg.GetSteamId(),
+		Reason:  steamprotocol.EDenyReason(msg.GetEdenyReason()),
+		Message: msg.GetDenyString(),
+	})
+}
+
+func (m *Module) handleKick(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgGSKick
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kick")
+	}
+
+	m.disapprove(msg.GetSteamId())
+
+	return m.eventManager.FireEvent(KickEvent{
+		SteamID: msg.GetSteamId(),
+		Reason:  steamprotocol.EDenyReason(msg.GetEdenyReason()),
+	})
+}
+
+// readToken return game connect token, which prefixes session auth ticket.
+func readToken(ticket []byte) ([]byte, error) {
+	if len(ticket) < 4 {
+		return nil, errors.New("ticket is too short")
+	}
+
+	size := binary.LittleEndian.Uint32(ticket)
+	if uint64(size) > uint64(len(ticket)-4) {
+		return nil, errors.New("invalid game connect token size")
+	}
+
+	return ticket[4 : 4+size], nil
+}
+
+// ipToUint32 convert IPv4 to integer used in messages.
+// Zero is returned for nil and IPv6 addresses.
+func ipToUint32(ip net.IP) uint32 {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(ip4)
+}
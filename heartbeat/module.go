@@ -1,22 +1,17 @@
 package heartbeat
 
 import (
-	"bytes"
 	"time"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/furdarius/steamprotocol"
 	"github.com/furdarius/steamprotocol/auth"
-	"github.com/furdarius/steamprotocol/messages"
 	"github.com/furdarius/steamprotocol/protobuf"
 )
 
 type Module struct {
 	eventManager *steamprotocol.EventManager
 	cl           *steamprotocol.Client
-	steamID      uint64
-	sessionID    int32
 	errorCh      chan error
 	doneCh       chan struct{}
 }
@@ -50,9 +45,6 @@ func (m *Module) handleEvent(e interface{}) error {
 }
 
 func (m *Module) handleSuccessfullyAuthenticatedEvent(e auth.SuccessfullyAuthenticatedEvent) {
-	m.sessionID = e.SessionID
-	m.steamID = e.SteamID
-
 	m.eventManager.FireEvent(HeartBeatStartingEvent{
 		Timeout: e.Heartbeat,
 	})
@@ -85,33 +77,9 @@ func (m *Module) heartbeatLoop(ticker *time.Ticker) {
 }
 
 func (m *Module) doTick() error {
-	responseHeader := messages.NewHeaderProto(steamprotocol.EMsg_ClientHeartBeat)
-
-	responseHeader.Data.Steamid = proto.Uint64(m.steamID)
-	responseHeader.Data.ClientSessionid = proto.Int32(m.sessionID)
-
-	responseMsg := &protobuf.CMsgClientHeartBeat{}
-
-	buf := new(bytes.Buffer)
-
-	err := responseHeader.Serialize(buf)
-	if err != nil {
-		return errors.Wrap(err, "failed to serialize header")
-	}
-
-	body, err := proto.Marshal(responseMsg)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal response msg")
-	}
-
-	_, err = buf.Write(body)
-	if err != nil {
-		return errors.Wrap(err, "failed to append msg to buffer")
-	}
-
-	err = m.cl.Write(buf.Bytes())
+	err := m.cl.Send(steamprotocol.EMsg_ClientHeartBeat, &protobuf.CMsgClientHeartBeat{})
 	if err != nil {
-		return errors.Wrap(err, "failed to write data")
+		return errors.Wrap(err, "failed to send heartbeat msg")
 	}
 
 	err = m.eventManager.FireEvent(HeartBeatTickedEvent{})
@@ -0,0 +1,170 @@
+package steamprotocol
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// InvalidJobID is used in headers of messages, which aren't related to any job.
+const InvalidJobID = ^uint64(0)
+
+// Job is a request sent to Steam, which waits for responses.
+// Steam sets target job id of response to source job id of request,
+// so responses are matched to job by it.
+type Job struct {
+	ID uint64
+
+	cl       *Client
+	mu       sync.Mutex
+	packets  []*Packet
+	notifyCh chan struct{}
+}
+
+// Wait block until response packet is received, or ctx is done.
+// Some requests have several responses, so Wait can be called again
+// to get next one.
+func (j *Job) Wait(ctx context.Context) (*Packet, error) {
+	for {
+		j.mu.Lock()
+		if len(j.packets) > 0 {
+			p := j.packets[0]
+			j.packets = j.packets[1:]
+			j.mu.Unlock()
+
+			return p, nil
+		}
+		j.mu.Unlock()
+
+		select {
+		case <-j.notifyCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close stop receiving responses for job.
+// It must be called when job responses are not needed anymore.
+func (j *Job) Close() {
+	j.cl.jobsMu.Lock()
+	delete(j.cl.jobs, j.ID)
+	j.cl.jobsMu.Unlock()
+}
+
+func (j *Job) push(p *Packet) {
+	j.mu.Lock()
+	j.packets = append(j.packets, p)
+	j.mu.Unlock()
+
+	select {
+	case j.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// SendJob write proto message with new source job id, and return Job
+// used to wait responses.
+func (c *Client) SendJob(eMsg EMsg, msg proto.Message) (*Job, error) {
+	return c.SendJobWithHeader(eMsg, &protobuf.CMsgProtoBufHeader{}, msg)
+}
+
+// SendJobWithHeader is same as SendJob, but allows to set custom header fields,
+// like TargetJobName of service method call. Source job id of header is overwritten.
+func (c *Client) SendJobWithHeader(eMsg EMsg, header *protobuf.CMsgProtoBufHeader, msg proto.Message) (*Job, error) {
+	job := c.newJob()
+
+	header.JobidSource = proto.Uint64(job.ID)
+
+	err := c.SendWithHeader(eMsg, header, msg)
+	if err != nil {
+		job.Close()
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Call write proto message as job and wait for the first response.
+func (c *Client) Call(ctx context.Context, eMsg EMsg, msg proto.Message) (*Packet, error) {
+	job, err := c.SendJob(eMsg, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	defer job.Close()
+
+	return job.Wait(ctx)
+}
+
+func (c *Client) newJob() *Job {
+	c.jobsMu.Lock()
+	defer c.jobsMu.Unlock()
+
+	c.lastJobID++
+
+	job := &Job{
+		ID:       c.lastJobID,
+		cl:       c,
+		notifyCh: make(chan struct{}, 1),
+	}
+
+	c.jobs[job.ID] = job
+
+	return job
+}
+
+// handleJobPacket pass packet to job, which waits for it.
+// It's called for every packet, so packets with header, which can't be read,
+// are skipped. Errors of such packets are left to module handling their EMsg.
+func (c *Client) handleJobPacket(p *Packet) error {
+	jobID, err := targetJobID(p.Data)
+	if err != nil || jobID == InvalidJobID {
+		return nil
+	}
+
+	c.jobsMu.Lock()
+	job, ok := c.jobs[jobID]
+	c.jobsMu.Unlock()
+
+	if ok {
+		job.push(p)
+	}
+
+	return nil
+}
+
+// targetJobID read target job id from header of any kind.
+func targetJobID(data []byte) (uint64, error) {
+	if len(data) < 4 {
+		return InvalidJobID, errors.New("packet is too short")
+	}
+
+	rawMsg := binary.LittleEndian.Uint32(data)
+
+	if rawMsg&ProtoMask > 0 {
+		header, _, err := readProtoHeader(data)
+		if err != nil {
+			return InvalidJobID, err
+		}
+
+		return header.GetJobidTarget(), nil
+	}
+
+	// Target job id follows EMsg in MsgHdr, and header size with version in ExtendedClientMsgHdr
+	offset := 4
+	if isExtendedHeader(data) {
+		offset = 7
+	}
+
+	if len(data) < offset+8 {
+		return InvalidJobID, nil
+	}
+
+	return binary.LittleEndian.Uint64(data[offset:]), nil
+}
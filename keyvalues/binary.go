@@ -0,0 +1,175 @@
+package keyvalues
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WriteBinary write KeyValues tree to w in binary format.
+// Root node is followed by additional End byte, as Steam expects.
+func (kv *KeyValue) WriteBinary(w io.Writer) error {
+	err := kv.writeNode(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte{byte(TypeEnd)})
+
+	return err
+}
+
+func (kv *KeyValue) writeNode(w io.Writer) error {
+	_, err := w.Write([]byte{byte(kv.Type)})
+	if err != nil {
+		return err
+	}
+
+	err = writeCString(w, kv.Name)
+	if err != nil {
+		return err
+	}
+
+	switch kv.Type {
+	case TypeNone:
+		for _, c := range kv.Children {
+			err = c.writeNode(w)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = w.Write([]byte{byte(TypeEnd)})
+
+		return err
+	case TypeString:
+		v, ok := kv.Value.(string)
+		if !ok {
+			return fmt.Errorf("invalid value of string node %q", kv.Name)
+		}
+
+		return writeCString(w, v)
+	case TypeInt32, TypeColor, TypePointer:
+		v, ok := kv.Value.(int32)
+		if !ok {
+			return fmt.Errorf("invalid value of int32 node %q", kv.Name)
+		}
+
+		return binary.Write(w, binary.LittleEndian, v)
+	case TypeFloat32:
+		v, ok := kv.Value.(float32)
+		if !ok {
+			return fmt.Errorf("invalid value of float32 node %q", kv.Name)
+		}
+
+		return binary.Write(w, binary.LittleEndian, v)
+	case TypeUint64:
+		v, ok := kv.Value.(uint64)
+		if !ok {
+			return fmt.Errorf("invalid value of uint64 node %q", kv.Name)
+		}
+
+		return binary.Write(w, binary.LittleEndian, v)
+	case TypeInt64:
+		v, ok := kv.Value.(int64)
+		if !ok {
+			return fmt.Errorf("invalid value of int64 node %q", kv.Name)
+		}
+
+		return binary.Write(w, binary.LittleEndian, v)
+	default:
+		return fmt.Errorf("unsupported type %d of node %q", kv.Type, kv.Name)
+	}
+}
+
+func writeCString(w io.Writer, s string) error {
+	_, err := io.WriteString(w, s)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte{0})
+
+	return err
+}
+
+// ReadBinary read KeyValues tree in binary format from r.
+func ReadBinary(r io.Reader) (*KeyValue, error) {
+	br := bufio.NewReader(r)
+
+	t, err := br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return readNode(br, Type(t))
+}
+
+func readNode(r *bufio.Reader, t Type) (*KeyValue, error) {
+	name, err := readCString(r)
+	if err != nil {
+		return nil, err
+	}
+
+	kv := &KeyValue{
+		Name: name,
+		Type: t,
+	}
+
+	switch t {
+	case TypeNone:
+		for {
+			ct, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+
+			if Type(ct) == TypeEnd {
+				return kv, nil
+			}
+
+			child, err := readNode(r, Type(ct))
+			if err != nil {
+				return nil, err
+			}
+
+			kv.Children = append(kv.Children, child)
+		}
+	case TypeString:
+		kv.Value, err = readCString(r)
+	case TypeInt32, TypeColor, TypePointer:
+		var v int32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		kv.Value = v
+	case TypeFloat32:
+		var v float32
+		err = binary.Read(r, binary.LittleEndian, &v)
+		kv.Value = v
+	case TypeUint64:
+		var v uint64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		kv.Value = v
+	case TypeInt64:
+		var v int64
+		err = binary.Read(r, binary.LittleEndian, &v)
+		kv.Value = v
+	default:
+		return nil, fmt.Errorf("unsupported type %d of node %q", t, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return kv, nil
+}
+
+func readCString(r *bufio.Reader) (string, error) {
+	s, err := r.ReadString(0)
+	if err != nil {
+		return "", err
+	}
+
+	return s[:len(s)-1], nil
+}
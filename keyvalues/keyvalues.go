@@ -0,0 +1,119 @@
+// Package keyvalues implements Valve's binary KeyValues format.
+//
+// KeyValues is a tree of named nodes. Every node is either an object with
+// child nodes, or a typed value. Binary representation of node is the type byte,
+// null-terminated name and value encoded according to the type.
+// Children of object are terminated with End type byte.
+package keyvalues
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Type is a type of binary KeyValues node.
+type Type byte
+
+const (
+	TypeNone       Type = 0
+	TypeString     Type = 1
+	TypeInt32      Type = 2
+	TypeFloat32    Type = 3
+	TypePointer    Type = 4
+	TypeWideString Type = 5
+	TypeColor      Type = 6
+	TypeUint64     Type = 7
+	TypeEnd        Type = 8
+	TypeInt64      Type = 10
+)
+
+// KeyValue is a node of KeyValues tree.
+// Value has Go type corresponding to Type: string, int32, float32, uint64 or int64.
+// Value of object node (TypeNone) is nil.
+type KeyValue struct {
+	Name     string
+	Type     Type
+	Value    interface{}
+	Children []*KeyValue
+}
+
+// NewObject initialize new object node with given children.
+func NewObject(name string, children ...*KeyValue) *KeyValue {
+	return &KeyValue{
+		Name:     name,
+		Type:     TypeNone,
+		Children: children,
+	}
+}
+
+// NewString initialize new string node.
+func NewString(name string, value string) *KeyValue {
+	return &KeyValue{
+		Name:  name,
+		Type:  TypeString,
+		Value: value,
+	}
+}
+
+// NewInt32 initialize new int32 node.
+func NewInt32(name string, value int32) *KeyValue {
+	return &KeyValue{
+		Name:  name,
+		Type:  TypeInt32,
+		Value: value,
+	}
+}
+
+// NewUint64 initialize new uint64 node.
+func NewUint64(name string, value uint64) *KeyValue {
+	return &KeyValue{
+		Name:  name,
+		Type:  TypeUint64,
+		Value: value,
+	}
+}
+
+// Child return first child node with given name, or nil if it isn't found.
+func (kv *KeyValue) Child(name string) *KeyValue {
+	for _, c := range kv.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// StringValue return value of node formatted as string.
+// Empty string is returned for object node.
+func (kv *KeyValue) StringValue() string {
+	switch v := kv.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// IntValue return value of node converted to int64.
+// Second result reports whether value is a number.
+func (kv *KeyValue) IntValue() (int64, bool) {
+	switch v := kv.Value.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case float32:
+		return int64(v), true
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
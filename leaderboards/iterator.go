@@ -0,0 +1,95 @@
+package leaderboards
+
+import (
+	"context"
+
+	"github.com/furdarius/steamprotocol"
+)
+
+// DefaultPageSize is a count of entries requested by one page of Iterator.
+const DefaultPageSize = 100
+
+// Iterator used to walk through global entries of leaderboard page by page.
+//
+//	it := m.GlobalEntries(lb, leaderboards.DefaultPageSize)
+//	for it.Next(ctx) {
+//		entry := it.Entry()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	m        *Module
+	lb       *Leaderboard
+	pageSize int32
+
+	start   int32
+	total   int32
+	entries []Entry
+	current Entry
+	done    bool
+	err     error
+}
+
+// GlobalEntries return Iterator over all entries of leaderboard, sorted by rank.
+func (m *Module) GlobalEntries(lb *Leaderboard, pageSize int32) *Iterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &Iterator{
+		m:        m,
+		lb:       lb,
+		pageSize: pageSize,
+		start:    1,
+	}
+}
+
+// Next advance iterator to the next entry, requesting next page when it's needed.
+// False is returned when entries are over, or error occurred.
+func (it *Iterator) Next(ctx context.Context) bool {
+	for len(it.entries) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		end := it.start + it.pageSize - 1
+
+		entries, total, err := it.m.GetEntries(ctx, it.lb, steamprotocol.ELeaderboardDataRequest_Global, it.start, end)
+		if err != nil {
+			it.err = err
+
+			return false
+		}
+
+		it.total = total
+		it.start = end + 1
+
+		if len(entries) == 0 || end >= total {
+			it.done = true
+		}
+
+		it.entries = entries
+	}
+
+	it.current = it.entries[0]
+	it.entries = it.entries[1:]
+
+	return true
+}
+
+// Entry return current entry of iterator.
+func (it *Iterator) Entry() Entry {
+	return it.current
+}
+
+// Total return total count of entries reported by Steam.
+// It's available after the first call of Next.
+func (it *Iterator) Total() int32 {
+	return it.total
+}
+
+// Err return error, which stopped iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
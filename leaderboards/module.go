@@ -0,0 +1,261 @@
+// Package leaderboards used to find leaderboards of apps, read their entries and upload scores.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package leaderboards
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Leaderboard is a leaderboard of app.
+type Leaderboard struct {
+	ID          int32
+	AppID       uint32
+	Name        string
+	EntryCount  int32
+	SortMethod  steamprotocol.ELeaderboardSortMethod
+	DisplayType steamprotocol.ELeaderboardDisplayType
+}
+
+// Entry is a score of user in leaderboard.
+// Details are arbitrary values defined by game.
+type Entry struct {
+	SteamID    uint64
+	GlobalRank int32
+	Score      int32
+	Details    []int32
+	UGCID      uint64
+}
+
+// ScoreResult is a result of score upload.
+type ScoreResult struct {
+	// Changed is false, if better score is kept with ELeaderboardUploadScoreMethod_KeepBest.
+	Changed            bool
+	GlobalRankPrevious int32
+	GlobalRankNew      int32
+	EntryCount         int32
+}
+
+// Module used to access leaderboards.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of leaderboards Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// FindLeaderboard return leaderboard of app by name.
+func (m *Module) FindLeaderboard(ctx context.Context, appID uint32, name string) (*Leaderboard, error) {
+	return m.findOrCreate(ctx, &protobuf.CMsgClientLBSFindOrCreateLB{
+		AppId:            proto.Uint32(appID),
+		CreateIfNotFound: proto.Bool(false),
+		LeaderboardName:  proto.String(name),
+	})
+}
+
+// FindOrCreateLeaderboard return leaderboard of app by name,
+// and create it with sortMethod and displayType, if it doesn't exist.
+func (m *Module) FindOrCreateLeaderboard(ctx context.Context, appID uint32, name string, sortMethod steamprotocol.ELeaderboardSortMethod, displayType steamprotocol.ELeaderboardDisplayType) (*Leaderboard, error) {
+	return m.findOrCreate(ctx, &protobuf.CMsgClientLBSFindOrCreateLB{
+		AppId:                  proto.Uint32(appID),
+		LeaderboardSortMethod:  proto.Int32(int32(sortMethod)),
+		LeaderboardDisplayType: proto.Int32(int32(displayType)),
+		CreateIfNotFound:       proto.Bool(true),
+		LeaderboardName:        proto.String(name),
+	})
+}
+
+func (m *Module) findOrCreate(ctx context.Context, req *protobuf.CMsgClientLBSFindOrCreateLB) (*Leaderboard, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientLBSFindOrCreateLB, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find leaderboard")
+	}
+
+	var msg protobuf.CMsgClientLBSFindOrCreateLBResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read leaderboard")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("leaderboard request failed with result %s", result.String())
+	}
+
+	return &Leaderboard{
+		ID:          msg.GetLeaderboardId(),
+		AppID:       req.GetAppId(),
+		Name:        msg.GetLeaderboardName(),
+		EntryCount:  msg.GetLeaderboardEntryCount(),
+		SortMethod:  steamprotocol.ELeaderboardSortMethod(msg.GetLeaderboardSortMethod()),
+		DisplayType: steamprotocol.ELeaderboardDisplayType(msg.GetLeaderboardDisplayType()),
+	}, nil
+}
+
+// GetEntries request entries of leaderboard in range.
+// For ELeaderboardDataRequest_Global range is 1-based ranks, both inclusive.
+// For ELeaderboardDataRequest_GlobalAroundUser range is relative to current user, like -5 and 5.
+// Range is ignored for ELeaderboardDataRequest_Friends.
+// Total count of entries is returned with entries.
+func (m *Module) GetEntries(ctx context.Context, lb *Leaderboard, request steamprotocol.ELeaderboardDataRequest, rangeStart, rangeEnd int32) ([]Entry, int32, error) {
+	return m.getEntries(ctx, &protobuf.CMsgClientLBSGetLBEntries{
+		AppId:                  proto.Int32(int32(lb.AppID)),
+		LeaderboardId:          proto.Int32(lb.ID),
+		RangeStart:             proto.Int32(rangeStart),
+		RangeEnd:               proto.Int32(rangeEnd),
+		LeaderboardDataRequest: proto.Int32(int32(request)),
+	})
+}
+
+// GetUserEntries request entries of users in leaderboard.
+func (m *Module) GetUserEntries(ctx context.Context, lb *Leaderboard, steamIDs ...uint64) ([]Entry, error) {
+	entries, _, err := m.getEntries(ctx, &protobuf.CMsgClientLBSGetLBEntries{
+		AppId:                  proto.Int32(int32(lb.AppID)),
+		LeaderboardId:          proto.Int32(lb.ID),
+		LeaderboardDataRequest: proto.Int32(int32(steamprotocol.ELeaderboardDataRequest_Users)),
+		Steamids:               steamIDs,
+	})
+
+	return entries, err
+}
+
+func (m *Module) getEntries(ctx context.Context, req *protobuf.CMsgClientLBSGetLBEntries) ([]Entry, int32, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientLBSGetLBEntries, req)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get leaderboard entries")
+	}
+
+	var msg protobuf.CMsgClientLBSGetLBEntriesResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to read leaderboard entries")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, 0, fmt.Errorf("leaderboard entries request failed with result %s", result.String())
+	}
+
+	entries := make([]Entry, 0, len(msg.GetEntries()))
+	for _, e := range msg.GetEntries() {
+		details, err := decodeDetails(e.GetDetails())
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, Entry{
+			SteamID:    e.GetSteamIdUser(),
+			GlobalRank: e.GetGlobalRank(),
+			Score:      e.GetScore(),
+			Details:    details,
+			UGCID:      e.GetUgcId(),
+		})
+	}
+
+	return entries, msg.GetLeaderboardEntryCount(), nil
+}
+
+// SetScore upload score of current user with details.
+// ELeaderboardUploadScoreMethod_KeepBest keeps previous score, if it's better,
+// and ELeaderboardUploadScoreMethod_ForceUpdate always replaces it.
+func (m *Module) SetScore(ctx context.Context, lb *Leaderboard, score int32, details []int32, method steamprotocol.ELeaderboardUploadScoreMethod) (*ScoreResult, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientLBSSetScore, &protobuf.CMsgClientLBSSetScore{
+		AppId:             proto.Uint32(lb.AppID),
+		LeaderboardId:     proto.Int32(lb.ID),
+		Score:             proto.Int32(score),
+		Details:           encodeDetails(details),
+		UploadScoreMethod: proto.Int32(int32(method)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set score")
+	}
+
+	var msg protobuf.CMsgClientLBSSetScoreResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read set score response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("score upload failed with result %s", result.String())
+	}
+
+	return &ScoreResult{
+		Changed:            msg.GetScoreChanged(),
+		GlobalRankPrevious: msg.GetGlobalRankPrevious(),
+		GlobalRankNew:      msg.GetGlobalRankNew(),
+		EntryCount:         msg.GetLeaderboardEntryCount(),
+	}, nil
+}
+
+// AttachUGC attach user generated content, like replay, to entry of current user.
+func (m *Module) AttachUGC(ctx context.Context, lb *Leaderboard, ugcID uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientLBSSetUGC, &protobuf.CMsgClientLBSSetUGC{
+		AppId:         proto.Uint32(lb.AppID),
+		LeaderboardId: proto.Int32(lb.ID),
+		UgcId:         proto.Uint64(ugcID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to attach ugc")
+	}
+
+	var msg protobuf.CMsgClientLBSSetUGCResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read set ugc response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("ugc attachment failed with result %s", result.String())
+	}
+
+	return nil
+}
+
+// encodeDetails serialize details as little endian int32 values.
+func encodeDetails(details []int32) []byte {
+	if len(details) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, details)
+
+	return buf.Bytes()
+}
+
+func decodeDetails(data []byte) ([]int32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid details size %d", len(data))
+	}
+
+	details := make([]int32, len(data)/4)
+
+	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, details)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read details")
+	}
+
+	return details, nil
+}
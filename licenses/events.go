@@ -0,0 +1,19 @@
+package licenses
+
+import "github.com/furdarius/steamprotocol"
+
+// LicenseListUpdatedEvent is fired when CMsgClientLicenseList is received.
+// Added, Removed and Changed are computed against previously received list.
+// Changed contains licenses with new change number, flags, type, payment method or owner.
+type LicenseListUpdatedEvent struct {
+	Licenses []License
+	Added    []License
+	Removed  []License
+	Changed  []License
+}
+
+// LicenseListFailedEvent is fired when CMsgClientLicenseList is received with not OK result.
+// Previously received list is kept.
+type LicenseListFailedEvent struct {
+	Result steamprotocol.EResult
+}
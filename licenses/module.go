@@ -0,0 +1,403 @@
+// Package licenses used to track account licenses and resolve owned apps.
+//
+// Steam sends ClientLicenseList right after logon, and every time licenses are changed.
+// License corresponds to package (sub), which grants apps and depots.
+// Contents of packages are requested via PICS (Product Info Cache Server):
+// ClientPICSAccessTokenRequest (client->server): Request access tokens for packages.
+// ClientPICSProductInfoRequest (client->server): Request packages info with access tokens.
+// ClientPICSProductInfoResponse (server->client): Packages info in binary KeyValues.
+// It can be split into several responses, while response_pending is set.
+package licenses
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/keyvalues"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// License is a package owned by account.
+type License struct {
+	PackageID     uint32
+	Type          steamprotocol.ELicenseType
+	Flags         steamprotocol.ELicenseFlags
+	PaymentMethod steamprotocol.EPaymentMethod
+	TimeCreated   time.Time
+	OwnerID       uint32
+	ChangeNumber  int32
+	// AccessToken is PICS access token of package.
+	// License list doesn't contain it, so it's filled after packages are resolved.
+	AccessToken uint64
+}
+
+// Package contains apps and depots granted by package.
+type Package struct {
+	ID       uint32
+	AppIDs   []uint32
+	DepotIDs []uint32
+}
+
+// Module used to track licenses.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	mu           sync.RWMutex
+	licenses     map[uint32]License
+	packages     map[uint32]Package
+}
+
+// NewModule initialize new instance of licenses Module.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		licenses:     make(map[uint32]License),
+		packages:     make(map[uint32]Package),
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientLicenseList:
+		return m.handleLicenseList(p)
+	}
+
+	return nil
+}
+
+// Licenses return current license list sorted by package id.
+func (m *Module) Licenses() []License {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sortedLicenses(m.licenses)
+}
+
+// OwnsApp reports whether any license grants app.
+func (m *Module) OwnsApp(ctx context.Context, appID uint32) (bool, error) {
+	apps, err := m.OwnedApps(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range apps {
+		if id == appID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// OwnedApps return sorted ids of apps granted by licenses.
+// Packages contents are requested from PICS, if they wasn't resolved yet.
+func (m *Module) OwnedApps(ctx context.Context) ([]uint32, error) {
+	packages, err := m.ResolvePackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint32]struct{})
+	for _, pkg := range packages {
+		for _, id := range pkg.AppIDs {
+			ids[id] = struct{}{}
+		}
+	}
+
+	return sortedIDs(ids), nil
+}
+
+// OwnedDepots return sorted ids of depots granted by licenses.
+// Packages contents are requested from PICS, if they wasn't resolved yet.
+func (m *Module) OwnedDepots(ctx context.Context) ([]uint32, error) {
+	packages, err := m.ResolvePackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint32]struct{})
+	for _, pkg := range packages {
+		for _, id := range pkg.DepotIDs {
+			ids[id] = struct{}{}
+		}
+	}
+
+	return sortedIDs(ids), nil
+}
+
+// ResolvePackages return contents of packages of current licenses.
+// Only packages, which wasn't resolved before, are requested from PICS.
+func (m *Module) ResolvePackages(ctx context.Context) ([]Package, error) {
+	m.mu.RLock()
+	var missing []uint32
+	for id := range m.licenses {
+		if _, ok := m.packages[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(missing) > 0 {
+		tokens, err := m.requestAccessTokens(ctx, missing)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to request package access tokens")
+		}
+
+		packages, err := m.requestPackagesInfo(ctx, missing, tokens)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to request packages info")
+		}
+
+		m.mu.Lock()
+		for _, pkg := range packages {
+			m.packages[pkg.ID] = pkg
+		}
+		for id, token := range tokens {
+			if l, ok := m.licenses[id]; ok {
+				l.AccessToken = token
+				m.licenses[id] = l
+			}
+		}
+		m.mu.Unlock()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]Package, 0, len(m.licenses))
+	for id := range m.licenses {
+		if pkg, ok := m.packages[id]; ok {
+			result = append(result, pkg)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+func (m *Module) handleLicenseList(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientLicenseList
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read license list")
+	}
+
+	// Failed list doesn't mean, that licenses are lost, so previous list is kept.
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return m.eventManager.FireEvent(LicenseListFailedEvent{
+			Result: result,
+		})
+	}
+
+	licenses := make(map[uint32]License, len(msg.GetLicenses()))
+	for _, l := range msg.GetLicenses() {
+		licenses[l.GetPackageId()] = License{
+			PackageID:     l.GetPackageId(),
+			Type:          steamprotocol.ELicenseType(l.GetLicenseType()),
+			Flags:         steamprotocol.ELicenseFlags(l.GetFlags()),
+			PaymentMethod: steamprotocol.EPaymentMethod(l.GetPaymentMethod()),
+			TimeCreated:   steamprotocol.UnixTime(l.GetTimeCreated()),
+			OwnerID:       l.GetOwnerId(),
+			ChangeNumber:  l.GetChangeNumber(),
+		}
+	}
+
+	event := LicenseListUpdatedEvent{}
+
+	m.mu.Lock()
+	for id, l := range licenses {
+		prev, ok := m.licenses[id]
+		if !ok {
+			event.Added = append(event.Added, l)
+
+			continue
+		}
+
+		l.AccessToken = prev.AccessToken
+		licenses[id] = l
+
+		if licenseChanged(prev, l) {
+			event.Changed = append(event.Changed, l)
+		}
+	}
+
+	for id, l := range m.licenses {
+		if _, ok := licenses[id]; !ok {
+			event.Removed = append(event.Removed, l)
+		}
+	}
+
+	m.licenses = licenses
+	event.Licenses = sortedLicenses(licenses)
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(event)
+}
+
+// licenseChanged report, whether license was changed in a new list.
+func licenseChanged(prev, l License) bool {
+	return prev.ChangeNumber != l.ChangeNumber ||
+		prev.Flags != l.Flags ||
+		prev.Type != l.Type ||
+		prev.PaymentMethod != l.PaymentMethod ||
+		prev.OwnerID != l.OwnerID
+}
+
+func (m *Module) requestAccessTokens(ctx context.Context, packageIDs []uint32) (map[uint32]uint64, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientPICSAccessTokenRequest, &protobuf.CMsgClientPICSAccessTokenRequest{
+		Packageids: packageIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var msg protobuf.CMsgClientPICSAccessTokenResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read access token response")
+	}
+
+	tokens := make(map[uint32]uint64, len(msg.GetPackageAccessTokens()))
+	for _, t := range msg.GetPackageAccessTokens() {
+		tokens[t.GetPackageid()] = t.GetAccessToken()
+	}
+
+	return tokens, nil
+}
+
+func (m *Module) requestPackagesInfo(
+	ctx context.Context,
+	packageIDs []uint32,
+	tokens map[uint32]uint64,
+) ([]Package, error) {
+	req := &protobuf.CMsgClientPICSProductInfoRequest{}
+	for _, id := range packageIDs {
+		req.Packages = append(req.Packages, &protobuf.CMsgClientPICSProductInfoRequest_PackageInfo{
+			Packageid:   proto.Uint32(id),
+			AccessToken: proto.Uint64(tokens[id]),
+		})
+	}
+
+	job, err := m.cl.SendJob(steamprotocol.EMsg_ClientPICSProductInfoRequest, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer job.Close()
+
+	var packages []Package
+
+	for {
+		p, err := job.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var msg protobuf.CMsgClientPICSProductInfoResponse
+
+		_, err = messages.ReadProto(p.Data, &msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read product info response")
+		}
+
+		for _, info := range msg.GetPackages() {
+			pkg, err := parsePackage(info.GetPackageid(), info.GetBuffer())
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse package %d", info.GetPackageid())
+			}
+
+			packages = append(packages, pkg)
+		}
+
+		// Unknown packages don't grant anything, but are cached to not request them again
+		for _, id := range msg.GetUnknownPackageids() {
+			packages = append(packages, Package{ID: id})
+		}
+
+		if !msg.GetResponsePending() {
+			return packages, nil
+		}
+	}
+}
+
+// parsePackage decode PICS package buffer.
+// Buffer starts with uint32 format marker, followed by binary KeyValues with "appids" and "depotids" lists.
+func parsePackage(id uint32, buf []byte) (Package, error) {
+	pkg := Package{
+		ID: id,
+	}
+
+	if len(buf) < 4 {
+		return pkg, errors.New("package buffer is empty")
+	}
+
+	kv, err := keyvalues.ReadBinary(bytes.NewReader(buf[4:]))
+	if err != nil {
+		return pkg, errors.Wrap(err, "failed to read package key values")
+	}
+
+	pkg.AppIDs = listIDs(kv.Child("appids"))
+	pkg.DepotIDs = listIDs(kv.Child("depotids"))
+
+	return pkg, nil
+}
+
+func listIDs(kv *keyvalues.KeyValue) []uint32 {
+	if kv == nil {
+		return nil
+	}
+
+	var ids []uint32
+	for _, c := range kv.Children {
+		if id, ok := c.IntValue(); ok {
+			ids = append(ids, uint32(id))
+		}
+	}
+
+	return ids
+}
+
+func sortedLicenses(licenses map[uint32]License) []License {
+	result := make([]License, 0, len(licenses))
+	for _, l := range licenses {
+		result = append(result, l)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PackageID < result[j].PackageID
+	})
+
+	return result
+}
+
+func sortedIDs(ids map[uint32]struct{}) []uint32 {
+	result := make([]uint32, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
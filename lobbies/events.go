@@ -0,0 +1,32 @@
+package lobbies
+
+// LobbyDataEvent is fired when CMsgClientMMSLobbyData is received.
+// Lobby is a copy, so it can be used in handlers safely.
+type LobbyDataEvent struct {
+	Lobby *Lobby
+}
+
+// UserJoinedEvent is fired when CMsgClientMMSUserJoinedLobby is received.
+type UserJoinedEvent struct {
+	AppID       uint32
+	LobbyID     uint64
+	SteamID     uint64
+	PersonaName string
+}
+
+// UserLeftEvent is fired when CMsgClientMMSUserLeftLobby is received.
+type UserLeftEvent struct {
+	AppID       uint32
+	LobbyID     uint64
+	SteamID     uint64
+	PersonaName string
+}
+
+// ChatMsgEvent is fired when CMsgClientMMSLobbyChatMsg is received.
+// Message is an arbitrary data defined by game.
+type ChatMsgEvent struct {
+	AppID         uint32
+	LobbyID       uint64
+	SenderSteamID uint64
+	Message       []byte
+}
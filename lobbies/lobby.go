@@ -0,0 +1,188 @@
+package lobbies
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/furdarius/steamprotocol/keyvalues"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/pkg/errors"
+)
+
+// LobbyType is a visibility of lobby.
+type LobbyType int32
+
+const (
+	LobbyTypePrivate     LobbyType = 0
+	LobbyTypeFriendsOnly LobbyType = 1
+	LobbyTypePublic      LobbyType = 2
+	LobbyTypeInvisible   LobbyType = 3
+)
+
+// FilterType is a type of lobby list filter.
+type FilterType int32
+
+const (
+	FilterTypeString         FilterType = 0
+	FilterTypeNumerical      FilterType = 1
+	FilterTypeSlotsAvailable FilterType = 2
+	FilterTypeNearValue      FilterType = 3
+	FilterTypeDistance       FilterType = 4
+)
+
+// Comparison is a comparison of lobby list filter.
+// Value of filter is compared with value of lobby.
+type Comparison int32
+
+const (
+	ComparisonEqualToOrLessThan    Comparison = -2
+	ComparisonLessThan             Comparison = -1
+	ComparisonEqual                Comparison = 0
+	ComparisonGreaterThan          Comparison = 1
+	ComparisonEqualToOrGreaterThan Comparison = 2
+	ComparisonNotEqual             Comparison = 3
+)
+
+// Filter is a filter of lobby list.
+type Filter struct {
+	Type       FilterType
+	Key        string
+	Value      string
+	Comparison Comparison
+}
+
+// Member is a member of lobby.
+type Member struct {
+	SteamID     uint64
+	PersonaName string
+	Metadata    map[string]string
+}
+
+// Lobby is a matchmaking lobby.
+// Members are known only for joined lobbies.
+type Lobby struct {
+	SteamID      uint64
+	AppID        uint32
+	OwnerSteamID uint64
+	Type         LobbyType
+	Flags        int32
+	MaxMembers   int32
+	NumMembers   int32
+	Metadata     map[string]string
+	Members      []Member
+	// Distance and Weight are set for lobbies returned by GetLobbyList.
+	Distance float32
+	Weight   int64
+}
+
+// Member return member of lobby by steamid.
+func (l *Lobby) Member(steamID uint64) (Member, bool) {
+	for _, m := range l.Members {
+		if m.SteamID == steamID {
+			return m, true
+		}
+	}
+
+	return Member{}, false
+}
+
+// copy return deep copy of lobby, so cached lobby isn't changed through it.
+func (l *Lobby) copy() *Lobby {
+	c := *l
+	c.Metadata = copyMetadata(l.Metadata)
+	c.Members = make([]Member, len(l.Members))
+
+	for i, m := range l.Members {
+		m.Metadata = copyMetadata(m.Metadata)
+		c.Members[i] = m
+	}
+
+	return &c
+}
+
+// encodeMetadata serialize metadata to binary KeyValues.
+func encodeMetadata(metadata map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	kv := keyvalues.NewObject("")
+	for _, k := range keys {
+		kv.Children = append(kv.Children, keyvalues.NewString(k, metadata[k]))
+	}
+
+	var buf bytes.Buffer
+
+	err := kv.WriteBinary(&buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write metadata")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeMetadata read metadata from binary KeyValues.
+// Empty metadata is returned for empty data.
+func decodeMetadata(data []byte) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	if len(data) == 0 {
+		return metadata, nil
+	}
+
+	kv, err := keyvalues.ReadBinary(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read metadata")
+	}
+
+	for _, c := range kv.Children {
+		metadata[c.Name] = c.StringValue()
+	}
+
+	return metadata, nil
+}
+
+func newLobbyData(msg *protobuf.CMsgClientMMSLobbyData) (*Lobby, error) {
+	metadata, err := decodeMetadata(msg.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	lobby := &Lobby{
+		SteamID:      msg.GetSteamIdLobby(),
+		AppID:        msg.GetAppId(),
+		OwnerSteamID: msg.GetSteamIdOwner(),
+		Type:         LobbyType(msg.GetLobbyType()),
+		Flags:        msg.GetLobbyFlags(),
+		MaxMembers:   msg.GetMaxMembers(),
+		NumMembers:   msg.GetNumMembers(),
+		Metadata:     metadata,
+	}
+
+	for _, m := range msg.GetMembers() {
+		member, err := newMember(m.GetSteamId(), m.GetPersonaName(), m.GetMetadata())
+		if err != nil {
+			return nil, err
+		}
+
+		lobby.Members = append(lobby.Members, member)
+	}
+
+	return lobby, nil
+}
+
+func newMember(steamID uint64, personaName string, data []byte) (Member, error) {
+	metadata, err := decodeMetadata(data)
+	if err != nil {
+		return Member{}, err
+	}
+
+	return Member{
+		SteamID:     steamID,
+		PersonaName: personaName,
+		Metadata:    metadata,
+	}, nil
+}
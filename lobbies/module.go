@@ -0,0 +1,570 @@
+// Package lobbies used to manage matchmaking lobbies of apps with MMS messages.
+//
+// Joined lobbies are cached, and kept up to date with
+// ClientMMSLobbyData, ClientMMSUserJoinedLobby and ClientMMSUserLeftLobby messages.
+// Metadata of lobbies and members is serialized as binary KeyValues.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package lobbies
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Module used to manage lobbies.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+
+	mu          sync.RWMutex
+	personaName string
+	publicIP    uint32
+	lobbies     map[uint64]*Lobby
+}
+
+// NewModule initialize new instance of lobbies Module.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		lobbies:      make(map[uint64]*Lobby),
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientLogOnResponse:
+		return m.handleLogOnResponse(p)
+	case steamprotocol.EMsg_ClientMMSLobbyData:
+		return m.handleLobbyData(p)
+	case steamprotocol.EMsg_ClientMMSUserJoinedLobby:
+		return m.handleUserJoined(p)
+	case steamprotocol.EMsg_ClientMMSUserLeftLobby:
+		return m.handleUserLeft(p)
+	case steamprotocol.EMsg_ClientMMSLobbyChatMsg:
+		return m.handleChatMsg(p)
+	}
+
+	return nil
+}
+
+// SetPersonaName change name, which is shown to other members of lobbies.
+func (m *Module) SetPersonaName(name string) {
+	m.mu.Lock()
+	m.personaName = name
+	m.mu.Unlock()
+}
+
+// Lobby return copy of joined lobby.
+// Nil is returned, if lobby isn't joined.
+func (m *Module) Lobby(lobbyID uint64) *Lobby {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lobby, ok := m.lobbies[lobbyID]
+	if !ok {
+		return nil
+	}
+
+	return lobby.copy()
+}
+
+// Lobbies return copies of joined lobbies.
+func (m *Module) Lobbies() []*Lobby {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lobbies := make([]*Lobby, 0, len(m.lobbies))
+	for _, l := range m.lobbies {
+		lobbies = append(lobbies, l.copy())
+	}
+
+	return lobbies
+}
+
+// CreateLobby create lobby owned by current user, and return its steamid.
+func (m *Module) CreateLobby(ctx context.Context, appID uint32, lobbyType LobbyType, maxMembers int32, metadata map[string]string) (uint64, error) {
+	data, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.RLock()
+	personaName, publicIP := m.personaName, m.publicIP
+	m.mu.RUnlock()
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSCreateLobby, &protobuf.CMsgClientMMSCreateLobby{
+		AppId:            proto.Uint32(appID),
+		MaxMembers:       proto.Int32(maxMembers),
+		LobbyType:        proto.Int32(int32(lobbyType)),
+		CellId:           proto.Uint32(m.cl.Session().CellID),
+		PublicIp:         proto.Uint32(publicIP),
+		Metadata:         data,
+		PersonaNameOwner: proto.String(personaName),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create lobby")
+	}
+
+	var msg protobuf.CMsgClientMMSCreateLobbyResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read create lobby response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return 0, fmt.Errorf("lobby creation failed with result %s", result.String())
+	}
+
+	steamID := m.cl.Session().SteamID
+
+	m.mu.Lock()
+	m.lobbies[msg.GetSteamIdLobby()] = &Lobby{
+		SteamID:      msg.GetSteamIdLobby(),
+		AppID:        appID,
+		OwnerSteamID: steamID,
+		Type:         lobbyType,
+		MaxMembers:   maxMembers,
+		NumMembers:   1,
+		Metadata:     copyMetadata(metadata),
+		Members: []Member{{
+			SteamID:     steamID,
+			PersonaName: personaName,
+			Metadata:    make(map[string]string),
+		}},
+	}
+	m.mu.Unlock()
+
+	return msg.GetSteamIdLobby(), nil
+}
+
+// JoinLobby join lobby, and return its copy.
+func (m *Module) JoinLobby(ctx context.Context, appID uint32, lobbyID uint64) (*Lobby, error) {
+	m.mu.RLock()
+	personaName := m.personaName
+	m.mu.RUnlock()
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSJoinLobby, &protobuf.CMsgClientMMSJoinLobby{
+		AppId:        proto.Uint32(appID),
+		SteamIdLobby: proto.Uint64(lobbyID),
+		PersonaName:  proto.String(personaName),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to join lobby")
+	}
+
+	var msg protobuf.CMsgClientMMSJoinLobbyResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read join lobby response")
+	}
+
+	response := steamprotocol.EChatRoomEnterResponse(msg.GetChatRoomEnterResponse())
+	if response != steamprotocol.EChatRoomEnterResponse_Success {
+		return nil, fmt.Errorf("lobby join failed with response %s", response.String())
+	}
+
+	metadata, err := decodeMetadata(msg.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	lobby := &Lobby{
+		SteamID:      msg.GetSteamIdLobby(),
+		AppID:        msg.GetAppId(),
+		OwnerSteamID: msg.GetSteamIdOwner(),
+		Type:         LobbyType(msg.GetLobbyType()),
+		Flags:        msg.GetLobbyFlags(),
+		MaxMembers:   msg.GetMaxMembers(),
+		NumMembers:   int32(len(msg.GetMembers())),
+		Metadata:     metadata,
+	}
+
+	for _, mem := range msg.GetMembers() {
+		member, err := newMember(mem.GetSteamId(), mem.GetPersonaName(), mem.GetMetadata())
+		if err != nil {
+			return nil, err
+		}
+
+		lobby.Members = append(lobby.Members, member)
+	}
+
+	m.mu.Lock()
+	m.lobbies[lobby.SteamID] = lobby
+	m.mu.Unlock()
+
+	return lobby.copy(), nil
+}
+
+// LeaveLobby leave joined lobby.
+func (m *Module) LeaveLobby(ctx context.Context, appID uint32, lobbyID uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSLeaveLobby, &protobuf.CMsgClientMMSLeaveLobby{
+		AppId:        proto.Uint32(appID),
+		SteamIdLobby: proto.Uint64(lobbyID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to leave lobby")
+	}
+
+	var msg protobuf.CMsgClientMMSLeaveLobbyResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read leave lobby response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("lobby leave failed with result %s", result.String())
+	}
+
+	m.mu.Lock()
+	delete(m.lobbies, lobbyID)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// GetLobbyList search lobbies of app matching all filters.
+// Lobbies are returned without members.
+func (m *Module) GetLobbyList(ctx context.Context, appID uint32, filters []Filter, maxLobbies int32) ([]*Lobby, error) {
+	m.mu.RLock()
+	publicIP := m.publicIP
+	m.mu.RUnlock()
+
+	req := &protobuf.CMsgClientMMSGetLobbyList{
+		AppId:               proto.Uint32(appID),
+		NumLobbiesRequested: proto.Int32(maxLobbies),
+		CellId:              proto.Uint32(m.cl.Session().CellID),
+		PublicIp:            proto.Uint32(publicIP),
+	}
+
+	for _, f := range filters {
+		req.Filters = append(req.Filters, &protobuf.CMsgClientMMSGetLobbyList_Filter{
+			Key:         proto.String(f.Key),
+			Value:       proto.String(f.Value),
+			Comparision: proto.Int32(int32(f.Comparison)),
+			FilterType:  proto.Int32(int32(f.Type)),
+		})
+	}
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSGetLobbyList, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get lobby list")
+	}
+
+	var msg protobuf.CMsgClientMMSGetLobbyListResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read lobby list")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("lobby list request failed with result %s", result.String())
+	}
+
+	lobbies := make([]*Lobby, 0, len(msg.GetLobbies()))
+	for _, l := range msg.GetLobbies() {
+		metadata, err := decodeMetadata(l.GetMetadata())
+		if err != nil {
+			return nil, err
+		}
+
+		lobbies = append(lobbies, &Lobby{
+			SteamID:    l.GetSteamId(),
+			AppID:      appID,
+			Type:       LobbyType(l.GetLobbyType()),
+			Flags:      l.GetLobbyFlags(),
+			MaxMembers: l.GetMaxMembers(),
+			NumMembers: l.GetNumMembers(),
+			Metadata:   metadata,
+			Distance:   l.GetDistance(),
+			Weight:     l.GetWeight(),
+		})
+	}
+
+	return lobbies, nil
+}
+
+// GetLobbyData request actual data of lobby.
+// Lobby doesn't have to be joined.
+func (m *Module) GetLobbyData(ctx context.Context, appID uint32, lobbyID uint64) (*Lobby, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSGetLobbyData, &protobuf.CMsgClientMMSGetLobbyData{
+		AppId:        proto.Uint32(appID),
+		SteamIdLobby: proto.Uint64(lobbyID),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get lobby data")
+	}
+
+	var msg protobuf.CMsgClientMMSLobbyData
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read lobby data")
+	}
+
+	return newLobbyData(&msg)
+}
+
+// SetLobbyData change type, size and metadata of lobby.
+// Only owner of lobby is allowed to change it.
+func (m *Module) SetLobbyData(ctx context.Context, appID uint32, lobbyID uint64, lobbyType LobbyType, maxMembers int32, metadata map[string]string) error {
+	data, err := encodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	return m.setLobbyData(ctx, &protobuf.CMsgClientMMSSetLobbyData{
+		AppId:         proto.Uint32(appID),
+		SteamIdLobby:  proto.Uint64(lobbyID),
+		SteamIdMember: proto.Uint64(0),
+		MaxMembers:    proto.Int32(maxMembers),
+		LobbyType:     proto.Int32(int32(lobbyType)),
+		Metadata:      data,
+	})
+}
+
+// SetMemberData change metadata of current user in lobby.
+func (m *Module) SetMemberData(ctx context.Context, appID uint32, lobbyID uint64, metadata map[string]string) error {
+	data, err := encodeMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	return m.setLobbyData(ctx, &protobuf.CMsgClientMMSSetLobbyData{
+		AppId:         proto.Uint32(appID),
+		SteamIdLobby:  proto.Uint64(lobbyID),
+		SteamIdMember: proto.Uint64(m.cl.Session().SteamID),
+		Metadata:      data,
+	})
+}
+
+func (m *Module) setLobbyData(ctx context.Context, req *protobuf.CMsgClientMMSSetLobbyData) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSSetLobbyData, req)
+	if err != nil {
+		return errors.Wrap(err, "failed to set lobby data")
+	}
+
+	var msg protobuf.CMsgClientMMSSetLobbyDataResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read set lobby data response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("lobby data update failed with result %s", result.String())
+	}
+
+	return nil
+}
+
+// SetLobbyOwner pass ownership of lobby to other member.
+func (m *Module) SetLobbyOwner(ctx context.Context, appID uint32, lobbyID uint64, ownerSteamID uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientMMSSetLobbyOwner, &protobuf.CMsgClientMMSSetLobbyOwner{
+		AppId:           proto.Uint32(appID),
+		SteamIdLobby:    proto.Uint64(lobbyID),
+		SteamIdNewOwner: proto.Uint64(ownerSteamID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to set lobby owner")
+	}
+
+	var msg protobuf.CMsgClientMMSSetLobbyOwnerResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read set lobby owner response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("lobby owner change failed with result %s", result.String())
+	}
+
+	m.mu.Lock()
+	if lobby, ok := m.lobbies[lobbyID]; ok {
+		lobby.OwnerSteamID = ownerSteamID
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// SendChatMsg send message to all members of lobby.
+func (m *Module) SendChatMsg(appID uint32, lobbyID uint64, message []byte) error {
+	return m.cl.Send(steamprotocol.EMsg_ClientMMSSendLobbyChatMsg, &protobuf.CMsgClientMMSSendLobbyChatMsg{
+		AppId:         proto.Uint32(appID),
+		SteamIdLobby:  proto.Uint64(lobbyID),
+		SteamIdTarget: proto.Uint64(0),
+		LobbyMessage:  message,
+	})
+}
+
+// InviteToLobby invite user to lobby.
+func (m *Module) InviteToLobby(appID uint32, lobbyID uint64, steamID uint64) error {
+	return m.cl.Send(steamprotocol.EMsg_ClientMMSInviteToLobby, &protobuf.CMsgClientMMSInviteToLobby{
+		AppId:              proto.Uint32(appID),
+		SteamIdLobby:       proto.Uint64(lobbyID),
+		SteamIdUserInvited: proto.Uint64(steamID),
+	})
+}
+
+func (m *Module) handleLogOnResponse(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientLogonResponse
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read logon response")
+	}
+
+	if steamprotocol.EResult(msg.GetEresult()) != steamprotocol.EResult_OK {
+		return nil
+	}
+
+	// Lobbies are left, when connection is lost.
+	m.mu.Lock()
+	m.publicIP = msg.GetPublicIp()
+	m.lobbies = make(map[uint64]*Lobby)
+	m.mu.Unlock()
+
+	return nil
+}
+
+func (m *Module) handleLobbyData(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientMMSLobbyData
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read lobby data")
+	}
+
+	lobby, err := newLobbyData(&msg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if cached, ok := m.lobbies[lobby.SteamID]; ok {
+		// Members aren't sent for some lobby types, so cached ones are kept.
+		if len(lobby.Members) == 0 {
+			lobby.Members = cached.Members
+		}
+
+		m.lobbies[lobby.SteamID] = lobby
+	}
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(LobbyDataEvent{
+		Lobby: lobby.copy(),
+	})
+}
+
+func (m *Module) handleUserJoined(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientMMSUserJoinedLobby
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read user joined lobby")
+	}
+
+	m.mu.Lock()
+	if lobby, ok := m.lobbies[msg.GetSteamIdLobby()]; ok {
+		if _, ok := lobby.Member(msg.GetSteamIdUser()); !ok {
+			lobby.Members = append(lobby.Members, Member{
+				SteamID:     msg.GetSteamIdUser(),
+				PersonaName: msg.GetPersonaName(),
+				Metadata:    make(map[string]string),
+			})
+			lobby.NumMembers++
+		}
+	}
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(UserJoinedEvent{
+		AppID:       msg.GetAppId(),
+		LobbyID:     msg.GetSteamIdLobby(),
+		SteamID:     msg.GetSteamIdUser(),
+		PersonaName: msg.GetPersonaName(),
+	})
+}
+
+func (m *Module) handleUserLeft(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientMMSUserLeftLobby
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read user left lobby")
+	}
+
+	m.mu.Lock()
+	if lobby, ok := m.lobbies[msg.GetSteamIdLobby()]; ok {
+		if msg.GetSteamIdUser() == m.cl.Session().SteamID {
+			delete(m.lobbies, lobby.SteamID)
+		} else {
+			members := lobby.Members[:0]
+			for _, mem := range lobby.Members {
+				if mem.SteamID != msg.GetSteamIdUser() {
+					members = append(members, mem)
+				}
+			}
+
+			lobby.NumMembers -= int32(len(lobby.Members) - len(members))
+			lobby.Members = members
+		}
+	}
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(UserLeftEvent{
+		AppID:       msg.GetAppId(),
+		LobbyID:     msg.GetSteamIdLobby(),
+		SteamID:     msg.GetSteamIdUser(),
+		PersonaName: msg.GetPersonaName(),
+	})
+}
+
+func (m *Module) handleChatMsg(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientMMSLobbyChatMsg
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read lobby chat message")
+	}
+
+	return m.eventManager.FireEvent(ChatMsgEvent{
+		AppID:         msg.GetAppId(),
+		LobbyID:       msg.GetSteamIdLobby(),
+		SenderSteamID: msg.GetSteamIdSender(),
+		Message:       msg.GetLobbyMessage(),
+	})
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	c := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		c[k] = v
+	}
+
+	return c
+}
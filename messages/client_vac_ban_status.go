@@ -0,0 +1,50 @@
+package messages
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/furdarius/steamprotocol"
+)
+
+// ClientVACBanStatus contains list of app ids, where account is VAC banned.
+type ClientVACBanStatus struct {
+	BannedApps []uint32
+}
+
+func (m *ClientVACBanStatus) Type() steamprotocol.EMsg {
+	return steamprotocol.EMsg_ClientVACBanStatus
+}
+
+func (m *ClientVACBanStatus) Serialize(w io.Writer) error {
+	err := binary.Write(w, binary.LittleEndian, uint32(len(m.BannedApps)))
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, binary.LittleEndian, m.BannedApps)
+}
+
+func (m *ClientVACBanStatus) Deserialize(r io.Reader) error {
+	var numBans uint32
+	err := binary.Read(r, binary.LittleEndian, &numBans)
+	if err != nil {
+		return err
+	}
+
+	// Apps are read one by one to not preallocate
+	// memory for invalid count of bans
+	m.BannedApps = nil
+
+	for i := uint32(0); i < numBans; i++ {
+		var appID uint32
+		err = binary.Read(r, binary.LittleEndian, &appID)
+		if err != nil {
+			return err
+		}
+
+		m.BannedApps = append(m.BannedApps, appID)
+	}
+
+	return nil
+}
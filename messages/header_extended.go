@@ -0,0 +1,84 @@
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/furdarius/steamprotocol"
+)
+
+// ExtendedHeader is ExtendedClientMsgHdr, used by non-protobuf messages
+// after the client has been assigned a session id or steamid.
+type ExtendedHeader struct {
+	Type        steamprotocol.EMsg
+	TargetJobID uint64
+	SourceJobID uint64
+	SteamID     uint64
+	SessionID   int32
+}
+
+func NewExtendedHeader(msgType steamprotocol.EMsg) *ExtendedHeader {
+	return &ExtendedHeader{
+		Type:        msgType,
+		TargetJobID: ^uint64(0),
+		SourceJobID: ^uint64(0),
+	}
+}
+
+func (m *ExtendedHeader) Serialize(w io.Writer) error {
+	fields := []interface{}{
+		m.Type,
+		steamprotocol.ExtendedHeaderSize,
+		steamprotocol.ExtendedHeaderVersion,
+		m.TargetJobID,
+		m.SourceJobID,
+		steamprotocol.ExtendedHeaderCanary,
+		m.SteamID,
+		m.SessionID,
+	}
+
+	for _, f := range fields {
+		err := binary.Write(w, binary.LittleEndian, f)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ExtendedHeader) Deserialize(r io.Reader) error {
+	var (
+		t       int32
+		size    uint8
+		version uint16
+		canary  uint8
+	)
+
+	fields := []interface{}{
+		&t,
+		&size,
+		&version,
+		&m.TargetJobID,
+		&m.SourceJobID,
+		&canary,
+		&m.SteamID,
+		&m.SessionID,
+	}
+
+	for _, f := range fields {
+		err := binary.Read(r, binary.LittleEndian, f)
+		if err != nil {
+			return err
+		}
+	}
+
+	if size != steamprotocol.ExtendedHeaderSize || canary != steamprotocol.ExtendedHeaderCanary {
+		return fmt.Errorf("invalid extended header: size %d, canary %d", size, canary)
+	}
+
+	m.Type = steamprotocol.EMsg(t)
+
+	return nil
+}
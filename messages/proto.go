@@ -0,0 +1,28 @@
+package messages
+
+import (
+	"bytes"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ReadProto deserialize header and body of proto message from packet data.
+func ReadProto(data []byte, msg proto.Message) (*HeaderProto, error) {
+	header := NewHeaderProto(steamprotocol.EMsg_Invalid)
+
+	dataBuf := bytes.NewBuffer(data)
+
+	err := header.Deserialize(dataBuf)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize header")
+	}
+
+	err = proto.Unmarshal(dataBuf.Bytes(), msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal msg")
+	}
+
+	return header, nil
+}
@@ -0,0 +1,158 @@
+// Package offline used to get offline logon tickets with Offline unified service.
+//
+// Offline ticket proves, that account was online at ticket creation time.
+// Ticket is signed by Steam with the universe private RSA key, so it can be
+// verified with the universe public key while CM servers are unreachable.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package offline
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	steamcrypto "github.com/furdarius/steamprotocol/crypto"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Ticket is a signed offline logon ticket.
+// JSON representation is used to persist ticket.
+type Ticket struct {
+	SerializedTicket []byte `json:"serialized_ticket"`
+	Signature        []byte `json:"signature"`
+}
+
+// TicketInfo is a content of offline logon ticket.
+type TicketInfo struct {
+	AccountID uint32
+	Created   time.Time
+}
+
+// ReadTicket decode Ticket from JSON.
+func ReadTicket(r io.Reader) (*Ticket, error) {
+	var t Ticket
+
+	err := json.NewDecoder(r).Decode(&t)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode ticket")
+	}
+
+	return &t, nil
+}
+
+// WriteTicket encode Ticket to JSON.
+func (t *Ticket) WriteTicket(w io.Writer) error {
+	return json.NewEncoder(w).Encode(t)
+}
+
+// Info decode content of ticket.
+// Signature isn't checked, so Verify must be used for untrusted tickets.
+func (t *Ticket) Info() (*TicketInfo, error) {
+	var msg unified.COffline_OfflineLogonTicket
+
+	err := proto.Unmarshal(t.SerializedTicket, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal ticket")
+	}
+
+	return newTicketInfo(&msg), nil
+}
+
+// Verify check signature of ticket with the universe public key,
+// and return content of ticket.
+func (t *Ticket) Verify(universe steamprotocol.EUniverse) (*TicketInfo, error) {
+	key, err := steamcrypto.PublicKey(universe)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha1.Sum(t.SerializedTicket)
+
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA1, hash[:], t.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ticket signature")
+	}
+
+	return t.Info()
+}
+
+// Validate verify ticket and check, that it belongs to account
+// and was created no longer than maxAge ago.
+func (t *Ticket) Validate(universe steamprotocol.EUniverse, accountID uint32, maxAge time.Duration) error {
+	info, err := t.Verify(universe)
+	if err != nil {
+		return err
+	}
+
+	if info.AccountID != accountID {
+		return fmt.Errorf("ticket belongs to account %d", info.AccountID)
+	}
+
+	if info.Created.IsZero() {
+		return errors.New("ticket creation time isn't set")
+	}
+
+	if time.Since(info.Created) > maxAge {
+		return fmt.Errorf("ticket is expired, it was created at %s", info.Created)
+	}
+
+	return nil
+}
+
+// Module used to get offline logon tickets.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of offline Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// GetOfflineLogonTicket request signed offline logon ticket of current account.
+func (m *Module) GetOfflineLogonTicket(ctx context.Context, priority uint32) (*Ticket, error) {
+	var resp unified.COffline_GetOfflineLogonTicket_Response
+
+	err := m.cl.CallService(ctx, "Offline.GetOfflineLogonTicket#1", &unified.COffline_GetOfflineLogonTicket_Request{
+		Priority: proto.Uint32(priority),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ticket{
+		SerializedTicket: resp.GetSerializedTicket(),
+		Signature:        resp.GetSignature(),
+	}, nil
+}
+
+// GetUnsignedOfflineLogonTicket request content of offline logon ticket without signature.
+func (m *Module) GetUnsignedOfflineLogonTicket(ctx context.Context) (*TicketInfo, error) {
+	var resp unified.COffline_GetUnsignedOfflineLogonTicket_Response
+
+	err := m.cl.CallService(ctx, "Offline.GetUnsignedOfflineLogonTicket#1", &unified.COffline_GetUnsignedOfflineLogonTicket_Request{}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTicketInfo(resp.GetTicket()), nil
+}
+
+func newTicketInfo(msg *unified.COffline_OfflineLogonTicket) *TicketInfo {
+	return &TicketInfo{
+		AccountID: msg.GetAccountid(),
+		Created:   steamprotocol.UnixTime(msg.GetRtime32CreationTime()),
+	}
+}
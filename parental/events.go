@@ -0,0 +1,18 @@
+package parental
+
+// SettingsUpdatedEvent is fired when parental settings are received
+// with CMsgClientLogonResponse, or changed by ParentalClient.NotifySettingsChange.
+// Settings is a copy, so it can be used in handlers safely.
+type SettingsUpdatedEvent struct {
+	Settings *Settings
+}
+
+// ParentalLockEvent is fired when Family View is locked.
+type ParentalLockEvent struct {
+	SessionID string
+}
+
+// ParentalUnlockEvent is fired when Family View is unlocked with PIN.
+type ParentalUnlockEvent struct {
+	SessionID string
+}
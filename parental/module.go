@@ -0,0 +1,242 @@
+// Package parental used to manage Family View of account.
+//
+// Serialized parental settings are sent in ClientLogOnResponse.
+// Account with enabled Family View is locked after logon,
+// and unlocked with PIN by Parental.ValidatePassword call.
+// Lock state changes are sent with ParentalClient notifications.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package parental
+
+import (
+	"context"
+	"sync"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Module used to manage Family View.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	mu           sync.RWMutex
+	settings     *Settings
+	locked       bool
+}
+
+// NewModule initialize new instance of parental Module.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientLogOnResponse:
+		return m.handleLogOnResponse(p)
+	case steamprotocol.EMsg_ServiceMethod, steamprotocol.EMsg_ClientServiceMethod:
+		return m.handleServiceMethod(p)
+	}
+
+	return nil
+}
+
+// Settings return copy of parental settings received at logon,
+// so it can be changed and passed to SetParentalSettings safely.
+// Nil is returned, if settings wasn't received.
+func (m *Module) Settings() *Settings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.settings == nil {
+		return nil
+	}
+
+	return m.settings.copy()
+}
+
+// Locked reports whether Family View is enabled and not unlocked with PIN.
+func (m *Module) Locked() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.locked
+}
+
+// GetParentalSettings request current parental settings of account.
+func (m *Module) GetParentalSettings(ctx context.Context) (*Settings, error) {
+	var resp unified.CParental_GetParentalSettings_Response
+
+	err := m.cl.CallService(ctx, "Parental.GetParentalSettings#1", &unified.CParental_GetParentalSettings_Request{
+		Steamid: proto.Uint64(m.cl.Session().SteamID),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSettings(resp.GetSettings()), nil
+}
+
+// SetParentalSettings change parental settings of account.
+// Current PIN is required, and it's changed to newPassword, if it's not empty.
+func (m *Module) SetParentalSettings(ctx context.Context, password string, settings *Settings, newPassword string) error {
+	req := &unified.CParental_SetParentalSettings_Request{
+		Password: proto.String(password),
+		Settings: settings.proto(),
+		Steamid:  proto.Uint64(m.cl.Session().SteamID),
+	}
+
+	if len(newPassword) > 0 {
+		req.NewPassword = proto.String(newPassword)
+	}
+
+	var resp unified.CParental_SetParentalSettings_Response
+
+	return m.cl.CallService(ctx, "Parental.SetParentalSettings#1", req, &resp)
+}
+
+// ValidatePassword check Family View PIN and return unlock token.
+// If unlock is set, Steam unlocks Family View for current session on success.
+func (m *Module) ValidatePassword(ctx context.Context, password string, unlock bool) (string, error) {
+	var resp unified.CParental_ValidatePassword_Response
+
+	err := m.cl.CallService(ctx, "Parental.ValidatePassword#1", &unified.CParental_ValidatePassword_Request{
+		Password:            proto.String(password),
+		SendUnlockOnSuccess: proto.Bool(unlock),
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetToken(), nil
+}
+
+// ValidateToken check unlock token returned by ValidatePassword.
+func (m *Module) ValidateToken(ctx context.Context, token string) error {
+	var resp unified.CParental_ValidateToken_Response
+
+	return m.cl.CallService(ctx, "Parental.ValidateToken#1", &unified.CParental_ValidateToken_Request{
+		UnlockToken: proto.String(token),
+	}, &resp)
+}
+
+// LockClient lock Family View again after it was unlocked.
+func (m *Module) LockClient(ctx context.Context) error {
+	var resp unified.CParental_LockClient_Response
+
+	return m.cl.CallService(ctx, "Parental.LockClient#1", &unified.CParental_LockClient_Request{}, &resp)
+}
+
+func (m *Module) handleLogOnResponse(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientLogonResponse
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read logon response")
+	}
+
+	if steamprotocol.EResult(msg.GetEresult()) != steamprotocol.EResult_OK {
+		return nil
+	}
+
+	if len(msg.GetParentalSettings()) == 0 {
+		m.mu.Lock()
+		m.settings = nil
+		m.locked = false
+		m.mu.Unlock()
+
+		return nil
+	}
+
+	return m.updateSettings(msg.GetParentalSettings(), true)
+}
+
+func (m *Module) handleServiceMethod(p *steamprotocol.Packet) error {
+	method, err := steamprotocol.ReadServiceMethod(p)
+	if err != nil {
+		return err
+	}
+
+	switch method.Name {
+	case "ParentalClient.NotifySettingsChange#1":
+		var msg unified.CParental_ParentalSettingsChange_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal parental settings change")
+		}
+
+		return m.updateSettings(msg.GetSerializedSettings(), false)
+	case "ParentalClient.NotifyLock#1":
+		var msg unified.CParental_ParentalLock_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal parental lock")
+		}
+
+		m.setLocked(true)
+
+		return m.eventManager.FireEvent(ParentalLockEvent{
+			SessionID: msg.GetSessionid(),
+		})
+	case "ParentalClient.NotifyUnlock#1":
+		var msg unified.CParental_ParentalUnlock_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal parental unlock")
+		}
+
+		m.setLocked(false)
+
+		return m.eventManager.FireEvent(ParentalUnlockEvent{
+			SessionID: msg.GetSessionid(),
+		})
+	}
+
+	return nil
+}
+
+// updateSettings decode serialized settings and store them.
+// Family View is locked after logon, when it's enabled.
+func (m *Module) updateSettings(data []byte, logon bool) error {
+	var msg unified.ParentalSettings
+
+	err := proto.Unmarshal(data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal parental settings")
+	}
+
+	settings := newSettings(&msg)
+
+	m.mu.Lock()
+	m.settings = settings
+	if logon || !settings.Enabled {
+		m.locked = settings.Enabled
+	}
+	m.mu.Unlock()
+
+	return m.eventManager.FireEvent(SettingsUpdatedEvent{
+		Settings: settings.copy(),
+	})
+}
+
+func (m *Module) setLocked(locked bool) {
+	m.mu.Lock()
+	m.locked = locked
+	m.mu.Unlock()
+}
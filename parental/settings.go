@@ -0,0 +1,150 @@
+package parental
+
+import (
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+)
+
+// Feature is a part of Steam, which can be restricted by Family View.
+type Feature uint32
+
+// Features, which can be restricted by Family View.
+const (
+	FeatureInvalid       Feature = 0
+	FeatureStore         Feature = 1
+	FeatureCommunity     Feature = 2
+	FeatureProfile       Feature = 3
+	FeatureFriends       Feature = 4
+	FeatureNews          Feature = 5
+	FeatureTrading       Feature = 6
+	FeatureSettings      Feature = 7
+	FeatureConsole       Feature = 8
+	FeatureBrowser       Feature = 9
+	FeatureParentalSetup Feature = 10
+	FeatureLibrary       Feature = 11
+	FeatureTest          Feature = 12
+)
+
+// App is an app listed in parental settings.
+type App struct {
+	AppID   uint32
+	Allowed bool
+}
+
+// Settings is Family View configuration of account.
+type Settings struct {
+	SteamID uint64
+	Enabled bool
+	// EnabledFeatures is a bit mask of features, which are available in Family View.
+	EnabledFeatures uint32
+
+	// Base app list is selected by parent, custom apps override it.
+	AppListBaseID          uint32
+	AppListBaseDescription string
+	AppListBase            []App
+	AppListCustom          []App
+
+	PasswordHashType uint32
+	Salt             []byte
+	PasswordHash     []byte
+	RecoveryEmail    string
+}
+
+// FeatureEnabled reports whether feature is available in Family View.
+func (s *Settings) FeatureEnabled(f Feature) bool {
+	return s.EnabledFeatures&(1<<uint32(f)) != 0
+}
+
+// SetFeature allow or restrict feature in Family View.
+func (s *Settings) SetFeature(f Feature, enabled bool) {
+	if enabled {
+		s.EnabledFeatures |= 1 << uint32(f)
+	} else {
+		s.EnabledFeatures &^= 1 << uint32(f)
+	}
+}
+
+// AppAllowed reports whether app can be played in Family View.
+// Custom app list takes precedence over base list.
+func (s *Settings) AppAllowed(appID uint32) bool {
+	for _, app := range s.AppListCustom {
+		if app.AppID == appID {
+			return app.Allowed
+		}
+	}
+
+	for _, app := range s.AppListBase {
+		if app.AppID == appID {
+			return app.Allowed
+		}
+	}
+
+	return false
+}
+
+// copy return deep copy of settings, so cached settings aren't changed through it.
+func (s *Settings) copy() *Settings {
+	c := *s
+	c.AppListBase = append([]App(nil), s.AppListBase...)
+	c.AppListCustom = append([]App(nil), s.AppListCustom...)
+	c.Salt = append([]byte(nil), s.Salt...)
+	c.PasswordHash = append([]byte(nil), s.PasswordHash...)
+
+	return &c
+}
+
+func newSettings(msg *unified.ParentalSettings) *Settings {
+	return &Settings{
+		SteamID:                msg.GetSteamid(),
+		Enabled:                msg.GetIsEnabled(),
+		EnabledFeatures:        msg.GetEnabledFeatures(),
+		AppListBaseID:          msg.GetApplistBaseId(),
+		AppListBaseDescription: msg.GetApplistBaseDescription(),
+		AppListBase:            newApps(msg.GetApplistBase()),
+		AppListCustom:          newApps(msg.GetApplistCustom()),
+		PasswordHashType:       msg.GetPasswordhashtype(),
+		Salt:                   msg.GetSalt(),
+		PasswordHash:           msg.GetPasswordhash(),
+		RecoveryEmail:          msg.GetRecoveryEmail(),
+	}
+}
+
+func newApps(apps []*unified.ParentalApp) []App {
+	result := make([]App, 0, len(apps))
+	for _, app := range apps {
+		result = append(result, App{
+			AppID:   app.GetAppid(),
+			Allowed: app.GetIsAllowed(),
+		})
+	}
+
+	return result
+}
+
+func (s *Settings) proto() *unified.ParentalSettings {
+	return &unified.ParentalSettings{
+		Steamid:                proto.Uint64(s.SteamID),
+		IsEnabled:              proto.Bool(s.Enabled),
+		EnabledFeatures:        proto.Uint32(s.EnabledFeatures),
+		ApplistBaseId:          proto.Uint32(s.AppListBaseID),
+		ApplistBaseDescription: proto.String(s.AppListBaseDescription),
+		ApplistBase:            protoApps(s.AppListBase),
+		ApplistCustom:          protoApps(s.AppListCustom),
+		Passwordhashtype:       proto.Uint32(s.PasswordHashType),
+		Salt:                   s.Salt,
+		Passwordhash:           s.PasswordHash,
+		RecoveryEmail:          proto.String(s.RecoveryEmail),
+	}
+}
+
+func protoApps(apps []App) []*unified.ParentalApp {
+	result := make([]*unified.ParentalApp, 0, len(apps))
+	for _, app := range apps {
+		result = append(result, &unified.ParentalApp{
+			Appid:     proto.Uint32(app.AppID),
+			IsAllowed: proto.Bool(app.Allowed),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,7 @@
+package player
+
+// LastPlayedTimesEvent is fired when PlayerClient.NotifyLastPlayedTimes is received.
+// Games contains only changed games, cache is updated with them.
+type LastPlayedTimesEvent struct {
+	Games []LastPlayed
+}
@@ -0,0 +1,214 @@
+// Package player used to call Player unified service and answer PlayerClient requests.
+//
+// Steam notifies client about played games with PlayerClient.NotifyLastPlayedTimes,
+// and asks for hardware survey with PlayerClient.GetSystemInformation.
+// Both are sent as service methods called by server.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package player
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Badge is a game badge of player.
+type Badge struct {
+	Level       int32
+	Series      int32
+	BorderColor uint32
+}
+
+// BadgeLevels contains Steam level of player and badges of game.
+type BadgeLevels struct {
+	PlayerLevel uint32
+	Badges      []Badge
+}
+
+// LastPlayed contains playtime of game.
+type LastPlayed struct {
+	AppID           uint32
+	LastPlayed      time.Time
+	Playtime2Weeks  time.Duration
+	PlaytimeForever time.Duration
+}
+
+// Module used to call Player service methods.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	mu           sync.RWMutex
+	lastPlayed   map[uint32]LastPlayed
+	systemInfo   *unified.CClientSystemInfo
+}
+
+// NewModule initialize new instance of player Module.
+// Until SetSystemInfo is called, only OS and CPU count are reported to Steam.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		lastPlayed:   make(map[uint32]LastPlayed),
+		systemInfo: &unified.CClientSystemInfo{
+			OperatingSystem: proto.String(runtime.GOOS),
+			Os_64Bit:        proto.Bool(runtime.GOARCH == "amd64" || runtime.GOARCH == "arm64"),
+			Cpu: &unified.CClientSystemInfo_CPU{
+				LogicalProcessors: proto.Int32(int32(runtime.NumCPU())),
+			},
+		},
+	}
+}
+
+// SetSystemInfo change system information, which is sent in response to
+// PlayerClient.GetSystemInformation.
+func (m *Module) SetSystemInfo(info *unified.CClientSystemInfo) {
+	m.mu.Lock()
+	m.systemInfo = info
+	m.mu.Unlock()
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ServiceMethod, steamprotocol.EMsg_ClientServiceMethod:
+		return m.handleServiceMethod(p)
+	}
+
+	return nil
+}
+
+// GameBadgeLevels return Steam level of player and badges of game.
+func (m *Module) GameBadgeLevels(ctx context.Context, appID uint32) (*BadgeLevels, error) {
+	var resp unified.CPlayer_GetGameBadgeLevels_Response
+
+	err := m.cl.CallService(ctx, "Player.GetGameBadgeLevels#1", &unified.CPlayer_GetGameBadgeLevels_Request{
+		Appid: proto.Uint32(appID),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := &BadgeLevels{
+		PlayerLevel: resp.GetPlayerLevel(),
+	}
+
+	for _, b := range resp.GetBadges() {
+		levels.Badges = append(levels.Badges, Badge{
+			Level:       b.GetLevel(),
+			Series:      b.GetSeries(),
+			BorderColor: b.GetBorderColor(),
+		})
+	}
+
+	return levels, nil
+}
+
+// LastPlayedTimes request games played after since, and update cache with them.
+// Zero time means all games.
+func (m *Module) LastPlayedTimes(ctx context.Context, since time.Time) ([]LastPlayed, error) {
+	var minLastPlayed uint32
+	if !since.IsZero() {
+		minLastPlayed = uint32(since.Unix())
+	}
+
+	var resp unified.CPlayer_GetLastPlayedTimes_Response
+
+	err := m.cl.CallService(ctx, "Player.ClientGetLastPlayedTimes#1", &unified.CPlayer_GetLastPlayedTimes_Request{
+		MinLastPlayed: proto.Uint32(minLastPlayed),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.updateLastPlayed(resp.GetGames()), nil
+}
+
+// CachedLastPlayedTimes return last played times, which were received before,
+// sorted by app id.
+func (m *Module) CachedLastPlayedTimes() []LastPlayed {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	games := make([]LastPlayed, 0, len(m.lastPlayed))
+	for _, g := range m.lastPlayed {
+		games = append(games, g)
+	}
+
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].AppID < games[j].AppID
+	})
+
+	return games
+}
+
+// AcceptSSA accept Steam Subscriber Agreement.
+func (m *Module) AcceptSSA(ctx context.Context) error {
+	var resp unified.CPlayer_AcceptSSA_Response
+
+	return m.cl.CallService(ctx, "Player.AcceptSSA#1", &unified.CPlayer_AcceptSSA_Request{}, &resp)
+}
+
+func (m *Module) handleServiceMethod(p *steamprotocol.Packet) error {
+	method, err := steamprotocol.ReadServiceMethod(p)
+	if err != nil {
+		return err
+	}
+
+	switch method.Name {
+	case "PlayerClient.NotifyLastPlayedTimes#1":
+		var msg unified.CPlayer_LastPlayedTimes_Notification
+
+		err = proto.Unmarshal(method.Body, &msg)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal last played times")
+		}
+
+		return m.eventManager.FireEvent(LastPlayedTimesEvent{
+			Games: m.updateLastPlayed(msg.GetGames()),
+		})
+	case "PlayerClient.GetSystemInformation#1":
+		m.mu.RLock()
+		info := m.systemInfo
+		m.mu.RUnlock()
+
+		return m.cl.RespondService(method, &unified.CPlayerClient_GetSystemInformation_Response{
+			SystemInfo: info,
+		})
+	}
+
+	return nil
+}
+
+func (m *Module) updateLastPlayed(games []*unified.CPlayer_GetLastPlayedTimes_Response_Game) []LastPlayed {
+	result := make([]LastPlayed, 0, len(games))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, g := range games {
+		lp := LastPlayed{
+			AppID:           uint32(g.GetAppid()),
+			LastPlayed:      time.Unix(int64(g.GetLastPlaytime()), 0),
+			Playtime2Weeks:  time.Duration(g.GetPlaytime_2Weeks()) * time.Minute,
+			PlaytimeForever: time.Duration(g.GetPlaytimeForever()) * time.Minute,
+		}
+
+		m.lastPlayed[lp.AppID] = lp
+		result = append(result, lp)
+	}
+
+	return result
+}
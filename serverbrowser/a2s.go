@@ -0,0 +1,248 @@
+package serverbrowser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultQueryTimeout is used for A2S queries, when ctx has no deadline.
+	DefaultQueryTimeout = 3 * time.Second
+
+	// maxPacketSize is a maximum size of A2S response, which isn't split.
+	maxPacketSize = 1400
+
+	a2sInfoRequest   = 'T'
+	a2sInfoResponse  = 'I'
+	a2sChallenge     = 'A'
+	a2sSinglePacket  = -1
+	a2sInfoQueryBody = "Source Engine Query\x00"
+
+	// Flags of extra data in A2S_INFO response.
+	edfPort     = 0x80
+	edfSteamID  = 0x10
+	edfSourceTV = 0x40
+	edfKeywords = 0x20
+	edfGameID   = 0x01
+)
+
+// Info is a response to A2S_INFO query.
+type Info struct {
+	Protocol    byte
+	Name        string
+	Map         string
+	Folder      string
+	Game        string
+	AppID       uint16
+	Players     byte
+	MaxPlayers  byte
+	Bots        byte
+	ServerType  byte
+	Environment byte
+	Passworded  bool
+	VAC         bool
+	Version     string
+	GamePort    uint16
+	SteamID     uint64
+	Keywords    string
+	GameID      uint64
+	// Ping is a round trip time of query.
+	Ping time.Duration
+}
+
+// QueryInfo send A2S_INFO query to server over UDP.
+// If server responds with challenge, query is repeated with it.
+func QueryInfo(ctx context.Context, addr string) (*Info, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultQueryTimeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to dial server")
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set deadline")
+	}
+
+	var challenge []byte
+
+	for {
+		start := time.Now()
+
+		_, err = conn.Write(infoRequest(challenge))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to send query")
+		}
+
+		buf := make([]byte, maxPacketSize)
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read response")
+		}
+
+		ping := time.Since(start)
+		data := buf[:n]
+
+		if len(data) < 5 || int32(binary.LittleEndian.Uint32(data)) != a2sSinglePacket {
+			return nil, errors.New("unsupported response packet")
+		}
+
+		switch data[4] {
+		case a2sChallenge:
+			if challenge != nil || len(data) < 9 {
+				return nil, errors.New("invalid challenge response")
+			}
+
+			challenge = data[5:9]
+		case a2sInfoResponse:
+			info, err := readInfo(data[5:])
+			if err != nil {
+				return nil, err
+			}
+
+			info.Ping = ping
+
+			return info, nil
+		default:
+			return nil, errors.Errorf("unexpected response type %#x", data[4])
+		}
+	}
+}
+
+func infoRequest(challenge []byte) []byte {
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, int32(a2sSinglePacket))
+	buf.WriteByte(a2sInfoRequest)
+	buf.WriteString(a2sInfoQueryBody)
+	buf.Write(challenge)
+
+	return buf.Bytes()
+}
+
+func readInfo(data []byte) (*Info, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+
+	var (
+		info Info
+		err  error
+	)
+
+	info.Protocol, err = r.ReadByte()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read protocol")
+	}
+
+	for _, s := range []*string{&info.Name, &info.Map, &info.Folder, &info.Game} {
+		*s, err = readString(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read server description")
+		}
+	}
+
+	var fixed struct {
+		AppID       uint16
+		Players     byte
+		MaxPlayers  byte
+		Bots        byte
+		ServerType  byte
+		Environment byte
+		Visibility  byte
+		VAC         byte
+	}
+
+	err = binary.Read(r, binary.LittleEndian, &fixed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read server details")
+	}
+
+	info.AppID = fixed.AppID
+	info.Players = fixed.Players
+	info.MaxPlayers = fixed.MaxPlayers
+	info.Bots = fixed.Bots
+	info.ServerType = fixed.ServerType
+	info.Environment = fixed.Environment
+	info.Passworded = fixed.Visibility == 1
+	info.VAC = fixed.VAC == 1
+
+	info.Version, err = readString(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read version")
+	}
+
+	edf, err := r.ReadByte()
+	if err != nil {
+		// Extra data is optional.
+		return &info, nil
+	}
+
+	if edf&edfPort != 0 {
+		err = binary.Read(r, binary.LittleEndian, &info.GamePort)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read game port")
+		}
+	}
+
+	if edf&edfSteamID != 0 {
+		err = binary.Read(r, binary.LittleEndian, &info.SteamID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read steamid")
+		}
+	}
+
+	if edf&edfSourceTV != 0 {
+		var port uint16
+
+		err = binary.Read(r, binary.LittleEndian, &port)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read sourcetv port")
+		}
+
+		_, err = readString(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read sourcetv name")
+		}
+	}
+
+	if edf&edfKeywords != 0 {
+		info.Keywords, err = readString(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read keywords")
+		}
+	}
+
+	if edf&edfGameID != 0 {
+		err = binary.Read(r, binary.LittleEndian, &info.GameID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read gameid")
+		}
+	}
+
+	return &info, nil
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	s, err := r.ReadString(0)
+	if err != nil {
+		return "", err
+	}
+
+	return s[:len(s)-1], nil
+}
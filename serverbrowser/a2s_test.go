@@ -0,0 +1,113 @@
+package serverbrowser
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+var testChallenge = []byte{0x11, 0x22, 0x33, 0x44}
+
+func testInfoResponse() []byte {
+	var buf bytes.Buffer
+
+	buf.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, a2sInfoResponse, 17})
+	buf.WriteString("Name\x00de_dust\x00csgo\x00CS\x00")
+	buf.Write([]byte{0xDA, 0x02, 5, 10, 0, 'd', 'l', 0, 1})
+	buf.WriteString("1.0\x00")
+	buf.Write([]byte{edfPort | edfKeywords, 0x87, 0x69})
+	buf.WriteString("kw\x00")
+
+	return buf.Bytes()
+}
+
+// newResponder start UDP server, which responds to A2S_INFO query with challenge,
+// and to query with challenge with response.
+func newResponder(t *testing.T, response []byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxPacketSize)
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			req := buf[:n]
+
+			if !bytes.Equal(req, infoRequest(testChallenge)) {
+				if !bytes.Equal(req, infoRequest(nil)) {
+					t.Errorf("unexpected request %x", req)
+
+					return
+				}
+
+				conn.WriteTo(append([]byte{0xFF, 0xFF, 0xFF, 0xFF, a2sChallenge}, testChallenge...), addr)
+
+				continue
+			}
+
+			conn.WriteTo(response, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestQueryInfo(t *testing.T) {
+	addr := newResponder(t, testInfoResponse())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	info, err := QueryInfo(ctx, addr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Info{
+		Protocol:    17,
+		Name:        "Name",
+		Map:         "de_dust",
+		Folder:      "csgo",
+		Game:        "CS",
+		AppID:       730,
+		Players:     5,
+		MaxPlayers:  10,
+		ServerType:  'd',
+		Environment: 'l',
+		VAC:         true,
+		Version:     "1.0",
+		GamePort:    27015,
+		Keywords:    "kw",
+		Ping:        info.Ping,
+	}
+
+	if *info != expected {
+		t.Fatalf("unexpected info %+v", *info)
+	}
+}
+
+func TestQueryInfoTruncated(t *testing.T) {
+	response := testInfoResponse()
+
+	// Response is cut inside of server details following server description.
+	addr := newResponder(t, response[:len("Name\x00de_dust\x00csgo\x00CS\x00")+9])
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := QueryInfo(ctx, addr)
+	if err == nil {
+		t.Fatal("expected error for truncated response")
+	}
+}
@@ -0,0 +1,144 @@
+// Package serverbrowser used to query game servers of app from master server.
+//
+// Servers are requested with ClientGMSServerQuery, and filtered
+// with filter string in format of master server, like "\gamedir\tf\secure\1".
+// Returned servers can be pinged with A2S_INFO query to get their details.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package serverbrowser
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// maxConcurrentQueries is a limit of A2S queries sent at once by PingServers.
+const maxConcurrentQueries = 32
+
+// Query is a filter of game servers.
+type Query struct {
+	AppID  uint32
+	Filter string
+	Region steamprotocol.ERegionCode
+	// GeoLocationIP is used to sort servers by distance, if it's set.
+	GeoLocationIP net.IP
+	// MaxServers limits count of returned servers, if it's not zero.
+	MaxServers uint32
+}
+
+// Server is a game server returned by master server.
+type Server struct {
+	IP          net.IP
+	QueryPort   uint16
+	AuthPlayers uint32
+	// Info is set by PingServers, if server responded.
+	Info *Info
+}
+
+// Addr return address of server used for A2S queries.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.IP.String(), strconv.Itoa(int(s.QueryPort)))
+}
+
+// Module used to query game servers.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of serverbrowser Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// QueryServers request game servers matching query from master server.
+func (m *Module) QueryServers(ctx context.Context, q Query) ([]Server, error) {
+	req := &protobuf.CMsgClientGMSServerQuery{
+		AppId:      proto.Uint32(q.AppID),
+		RegionCode: proto.Uint32(uint32(q.Region)),
+		FilterText: proto.String(q.Filter),
+	}
+
+	if ip := q.GeoLocationIP.To4(); ip != nil {
+		req.GeoLocationIp = proto.Uint32(binary.BigEndian.Uint32(ip))
+	}
+
+	if q.MaxServers > 0 {
+		req.MaxServers = proto.Uint32(q.MaxServers)
+	}
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientGMSServerQuery, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query servers")
+	}
+
+	var msg protobuf.CMsgGMSClientServerQueryResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read servers")
+	}
+
+	if msg.Error != nil {
+		return nil, fmt.Errorf("server query failed: %s", msg.GetError())
+	}
+
+	servers := make([]Server, 0, len(msg.GetServers()))
+	for _, s := range msg.GetServers() {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, s.GetServerIp())
+
+		servers = append(servers, Server{
+			IP:          ip,
+			QueryPort:   uint16(s.GetServerPort()),
+			AuthPlayers: s.GetAuthPlayers(),
+		})
+	}
+
+	return servers, nil
+}
+
+// PingServers send A2S_INFO query to every server, and set Info of responded servers.
+// Servers, which didn't respond until ctx is done, are left without Info.
+func PingServers(ctx context.Context, servers []Server) {
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, maxConcurrentQueries)
+
+	for i := range servers {
+		wg.Add(1)
+
+		go func(s *Server) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			defer func() { <-sem }()
+
+			info, err := QueryInfo(ctx, s.Addr())
+			if err != nil {
+				return
+			}
+
+			s.Info = info
+		}(&servers[i])
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,154 @@
+package steamprotocol
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// CallService call unified service method, like "Credentials.GetSteamGuardDetails#1",
+// and unmarshal method response to resp.
+//
+// Unified messages are wrapped into ClientServiceMethod, and response is
+// sent back with ClientServiceMethodResponse. EResult of call is set in response header.
+func (c *Client) CallService(ctx context.Context, method string, req proto.Message, resp proto.Message) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal method request")
+	}
+
+	p, err := c.Call(ctx, EMsg_ClientServiceMethod, &protobuf.CMsgClientServiceMethod{
+		MethodName:       proto.String(method),
+		SerializedMethod: body,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to call service method %s", method)
+	}
+
+	header, bodyOffset, err := readProtoHeader(p.Data)
+	if err != nil {
+		return errors.Wrap(err, "failed to read method response header")
+	}
+
+	result := EResult(header.GetEresult())
+	if result != EResult_OK {
+		return fmt.Errorf("service method %s failed with result %s", method, result.String())
+	}
+
+	var msg protobuf.CMsgClientServiceMethodResponse
+
+	err = proto.Unmarshal(p.Data[bodyOffset:], &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal method response")
+	}
+
+	err = proto.Unmarshal(msg.GetSerializedMethodResponse(), resp)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s response", method)
+	}
+
+	return nil
+}
+
+// NotifyService send unified service notification, which has no response.
+func (c *Client) NotifyService(method string, req proto.Message) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal method request")
+	}
+
+	return c.Send(EMsg_ClientServiceMethod, &protobuf.CMsgClientServiceMethod{
+		MethodName:       proto.String(method),
+		SerializedMethod: body,
+		IsNotification:   proto.Bool(true),
+	})
+}
+
+// ServiceMethod is unified service method call or notification sent by Steam.
+type ServiceMethod struct {
+	// Name of method, like "ParentalClient.NotifyLock#1".
+	Name string
+	// Body is serialized method request.
+	Body []byte
+	// SourceJobID is used to respond to method call.
+	// It's equal to InvalidJobID for notifications.
+	SourceJobID uint64
+
+	eMsg EMsg
+}
+
+// IsNotification reports whether Steam expects no response to method.
+func (m *ServiceMethod) IsNotification() bool {
+	return m.SourceJobID == InvalidJobID
+}
+
+// ReadServiceMethod decode ServiceMethod or ClientServiceMethod packet sent by Steam.
+// Method name of ServiceMethod is set in target job name of header,
+// and ClientServiceMethod wraps name and request into message body.
+func ReadServiceMethod(p *Packet) (*ServiceMethod, error) {
+	header, bodyOffset, err := readProtoHeader(p.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read service method header")
+	}
+
+	method := &ServiceMethod{
+		SourceJobID: header.GetJobidSource(),
+		eMsg:        p.Type,
+	}
+
+	switch p.Type {
+	case EMsg_ServiceMethod:
+		method.Name = header.GetTargetJobName()
+		method.Body = p.Data[bodyOffset:]
+	case EMsg_ClientServiceMethod:
+		var msg protobuf.CMsgClientServiceMethod
+
+		err = proto.Unmarshal(p.Data[bodyOffset:], &msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal service method")
+		}
+
+		method.Name = msg.GetMethodName()
+		method.Body = msg.GetSerializedMethod()
+
+		if msg.GetIsNotification() {
+			method.SourceJobID = InvalidJobID
+		}
+	default:
+		return nil, fmt.Errorf("packet %s is not a service method", p.Type.String())
+	}
+
+	return method, nil
+}
+
+// RespondService send response to service method called by Steam.
+// Response is sent with the same kind of message, which method was received with.
+func (c *Client) RespondService(method *ServiceMethod, resp proto.Message) error {
+	if method.IsNotification() {
+		return fmt.Errorf("service method %s is a notification", method.Name)
+	}
+
+	header := &protobuf.CMsgProtoBufHeader{
+		JobidTarget: proto.Uint64(method.SourceJobID),
+		Eresult:     proto.Int32(int32(EResult_OK)),
+	}
+
+	if method.eMsg == EMsg_ServiceMethod {
+		header.TargetJobName = proto.String(method.Name)
+
+		return c.SendWithHeader(EMsg_ServiceMethodResponse, header, resp)
+	}
+
+	body, err := proto.Marshal(resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal method response")
+	}
+
+	return c.SendWithHeader(EMsg_ClientServiceMethodResponse, header, &protobuf.CMsgClientServiceMethodResponse{
+		MethodName:               proto.String(method.Name),
+		SerializedMethodResponse: body,
+	})
+}
@@ -0,0 +1,166 @@
+package steamprotocol
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ExtendedHeaderSize is the length of ExtendedClientMsgHdr in bytes.
+	ExtendedHeaderSize uint8 = 36
+
+	// ExtendedHeaderVersion is the version of ExtendedClientMsgHdr.
+	ExtendedHeaderVersion uint16 = 2
+
+	// ExtendedHeaderCanary is a magic byte placed after job ids in ExtendedClientMsgHdr.
+	ExtendedHeaderCanary uint8 = 239
+
+	// Offsets of session fields in serialized ExtendedClientMsgHdr.
+	extendedHeaderCanaryOffset    = 23
+	extendedHeaderSteamIDOffset   = 24
+	extendedHeaderSessionIDOffset = 32
+)
+
+// Session contains identifiers assigned to client by Steam after successful logon.
+// Every message after ClientLogOn needs the steamid and sessionID set in the header.
+type Session struct {
+	SteamID   uint64
+	SessionID int32
+	CellID    uint32
+}
+
+// IsEmpty reports whether session was not assigned yet.
+func (s Session) IsEmpty() bool {
+	return s.SteamID == 0
+}
+
+// SetSession change session used to stamp outgoing message headers.
+func (c *Client) SetSession(s Session) {
+	c.sessionMu.Lock()
+	c.session = s
+	c.sessionMu.Unlock()
+}
+
+// Session return current session of Client.
+// Empty session is returned before successful logon.
+func (c *Client) Session() Session {
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+
+	return c.session
+}
+
+// ResetSession drop current session, so outgoing headers
+// will be sent as is until new session is set.
+func (c *Client) ResetSession() {
+	c.SetSession(Session{})
+}
+
+// stampSession write SteamID and session ID of current session
+// to proto or extended header of outgoing message.
+// Messages with other headers are returned unchanged.
+func (c *Client) stampSession(data []byte) ([]byte, error) {
+	s := c.Session()
+	if s.IsEmpty() || len(data) < 4 {
+		return data, nil
+	}
+
+	rawMsg := binary.LittleEndian.Uint32(data)
+
+	if rawMsg&ProtoMask > 0 {
+		return stampProtoHeader(data, s)
+	}
+
+	if isExtendedHeader(data) {
+		stamped := make([]byte, len(data))
+		copy(stamped, data)
+
+		binary.LittleEndian.PutUint64(stamped[extendedHeaderSteamIDOffset:], s.SteamID)
+		binary.LittleEndian.PutUint32(stamped[extendedHeaderSessionIDOffset:], uint32(s.SessionID))
+
+		return stamped, nil
+	}
+
+	return data, nil
+}
+
+func stampProtoHeader(data []byte, s Session) ([]byte, error) {
+	header, bodyOffset, err := readProtoHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header.Steamid = proto.Uint64(s.SteamID)
+	header.ClientSessionid = proto.Int32(s.SessionID)
+
+	buf := new(bytes.Buffer)
+
+	err = writeProtoHeader(buf, binary.LittleEndian.Uint32(data), header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize proto header")
+	}
+
+	_, err = buf.Write(data[bodyOffset:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to append msg to buffer")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readProtoHeader decode header of proto message,
+// and return offset of message body in data.
+func readProtoHeader(data []byte) (*protobuf.CMsgProtoBufHeader, int, error) {
+	if len(data) < 8 {
+		return nil, 0, errors.New("proto message is too short")
+	}
+
+	headerLen := int(int32(binary.LittleEndian.Uint32(data[4:8])))
+	if headerLen < 0 || len(data) < 8+headerLen {
+		return nil, 0, errors.New("invalid proto header length")
+	}
+
+	var header protobuf.CMsgProtoBufHeader
+
+	err := proto.Unmarshal(data[8:8+headerLen], &header)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to unmarshal proto header")
+	}
+
+	return &header, 8 + headerLen, nil
+}
+
+func writeProtoHeader(buf *bytes.Buffer, rawMsg uint32, header *protobuf.CMsgProtoBufHeader) error {
+	headerBuf, err := proto.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	err = binary.Write(buf, binary.LittleEndian, rawMsg|ProtoMask)
+	if err != nil {
+		return err
+	}
+
+	err = binary.Write(buf, binary.LittleEndian, int32(len(headerBuf)))
+	if err != nil {
+		return err
+	}
+
+	_, err = buf.Write(headerBuf)
+
+	return err
+}
+
+func isExtendedHeader(data []byte) bool {
+	if len(data) < int(ExtendedHeaderSize) {
+		return false
+	}
+
+	return data[4] == ExtendedHeaderSize &&
+		binary.LittleEndian.Uint16(data[5:7]) == ExtendedHeaderVersion &&
+		data[extendedHeaderCanaryOffset] == ExtendedHeaderCanary
+}
@@ -0,0 +1,192 @@
+// Package store used to activate licenses on account.
+//
+// ClientRequestFreeLicense (client->server): Request free licenses for apps.
+// ClientRequestFreeLicenseResponse (server->client): Granted packages and apps.
+// ClientRegisterKey (client->server): Register CD key on account.
+// ClientPurchaseResponse (server->client): EResult with purchase result detail, and
+// receipt in binary KeyValues, which contains granted packages.
+// ClientRedeemGuestPass (client->server): Redeem guest pass by id.
+// ClientRedeemGuestPassResponse (server->client): EResult and granted package.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/keyvalues"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// PurchaseError is returned, when Steam refused to register key or redeem guest pass.
+type PurchaseError struct {
+	Result steamprotocol.EResult
+	Detail steamprotocol.EPurchaseResultDetail
+}
+
+func (e *PurchaseError) Error() string {
+	return fmt.Sprintf("purchase failed with result %s, detail %s", e.Result.String(), e.Detail.String())
+}
+
+// FreeLicenseResult contains licenses granted by free license request.
+type FreeLicenseResult struct {
+	GrantedPackages []uint32
+	GrantedApps     []uint32
+}
+
+// LineItem is a package in purchase receipt.
+type LineItem struct {
+	PackageID   uint32
+	Description string
+}
+
+// PurchaseReceipt is result of key registration.
+type PurchaseReceipt struct {
+	Detail    steamprotocol.EPurchaseResultDetail
+	LineItems []LineItem
+}
+
+// GrantedPackages return ids of packages from receipt line items.
+func (r *PurchaseReceipt) GrantedPackages() []uint32 {
+	ids := make([]uint32, 0, len(r.LineItems))
+	for _, item := range r.LineItems {
+		ids = append(ids, item.PackageID)
+	}
+
+	return ids
+}
+
+// Module used to request free licenses, register keys and redeem guest passes.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of store Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// RequestFreeLicense request free licenses for apps.
+// Apps, which aren't free, are silently skipped by Steam, so result must be checked.
+func (m *Module) RequestFreeLicense(ctx context.Context, appIDs ...uint32) (*FreeLicenseResult, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientRequestFreeLicense, &protobuf.CMsgClientRequestFreeLicense{
+		Appids: appIDs,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request free license")
+	}
+
+	var msg protobuf.CMsgClientRequestFreeLicenseResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read free license response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("free license request failed with result %s", result.String())
+	}
+
+	return &FreeLicenseResult{
+		GrantedPackages: msg.GetGrantedPackageids(),
+		GrantedApps:     msg.GetGrantedAppids(),
+	}, nil
+}
+
+// RegisterKey register CD key on account.
+// *PurchaseError is returned, if key was refused.
+func (m *Module) RegisterKey(ctx context.Context, key string) (*PurchaseReceipt, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientRegisterKey, &protobuf.CMsgClientRegisterKey{
+		Key: proto.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register key")
+	}
+
+	var msg protobuf.CMsgClientPurchaseResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read purchase response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	detail := steamprotocol.EPurchaseResultDetail(msg.GetPurchaseResultDetails())
+
+	if result != steamprotocol.EResult_OK {
+		return nil, &PurchaseError{
+			Result: result,
+			Detail: detail,
+		}
+	}
+
+	receipt := &PurchaseReceipt{
+		Detail: detail,
+	}
+
+	if len(msg.GetPurchaseReceiptInfo()) == 0 {
+		return receipt, nil
+	}
+
+	kv, err := keyvalues.ReadBinary(bytes.NewReader(msg.GetPurchaseReceiptInfo()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read purchase receipt")
+	}
+
+	if items := kv.Child("lineitems"); items != nil {
+		for _, item := range items.Children {
+			var li LineItem
+
+			if id := item.Child("PackageID"); id != nil {
+				n, _ := id.IntValue()
+				li.PackageID = uint32(n)
+			}
+
+			if desc := item.Child("ItemDescription"); desc != nil {
+				li.Description = desc.StringValue()
+			}
+
+			receipt.LineItems = append(receipt.LineItems, li)
+		}
+	}
+
+	return receipt, nil
+}
+
+// RedeemGuestPass redeem guest pass and return id of granted package.
+// *PurchaseError is returned, if guest pass was refused.
+func (m *Module) RedeemGuestPass(ctx context.Context, guestPassID uint64) (uint32, error) {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientRedeemGuestPass, &protobuf.CMsgClientRedeemGuestPass{
+		GuestPassId: proto.Uint64(guestPassID),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to redeem guest pass")
+	}
+
+	var msg protobuf.CMsgClientRedeemGuestPassResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read redeem guest pass response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return 0, &PurchaseError{
+			Result: result,
+			Detail: steamprotocol.EPurchaseResultDetail_NoDetail,
+		}
+	}
+
+	return msg.GetPackageId(), nil
+}
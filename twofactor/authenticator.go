@@ -0,0 +1,51 @@
+package twofactor
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Authenticator contains secrets of mobile authenticator.
+// JSON representation is compatible with maFile format of mobile authenticator tools.
+type Authenticator struct {
+	SharedSecret   string         `json:"shared_secret"`
+	SerialNumber   string         `json:"serial_number"`
+	RevocationCode string         `json:"revocation_code"`
+	URI            string         `json:"uri"`
+	ServerTime     int64          `json:"server_time"`
+	AccountName    string         `json:"account_name"`
+	TokenGID       string         `json:"token_gid"`
+	IdentitySecret string         `json:"identity_secret"`
+	Secret1        string         `json:"secret_1"`
+	Status         int32          `json:"status"`
+	DeviceID       string         `json:"device_id"`
+	FullyEnrolled  bool           `json:"fully_enrolled"`
+	Session        *MaFileSession `json:"Session,omitempty"`
+}
+
+// MaFileSession is web session stored in maFile.
+type MaFileSession struct {
+	SessionID        string `json:"SessionID"`
+	SteamLogin       string `json:"SteamLogin"`
+	SteamLoginSecure string `json:"SteamLoginSecure"`
+	WebCookie        string `json:"WebCookie"`
+	OAuthToken       string `json:"OAuthToken"`
+	SteamID          uint64 `json:"SteamID"`
+}
+
+// ReadMaFile decode Authenticator from maFile JSON.
+func ReadMaFile(r io.Reader) (*Authenticator, error) {
+	var a Authenticator
+
+	err := json.NewDecoder(r).Decode(&a)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// WriteMaFile encode Authenticator to maFile JSON.
+func (a *Authenticator) WriteMaFile(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a)
+}
@@ -0,0 +1,251 @@
+// Package twofactor used to add and remove Steam Guard mobile authenticator.
+//
+// Enrollment consists of two steps:
+// 1. AddAuthenticator returns secrets of new authenticator, and Steam sends SMS code.
+// 2. FinalizeAddAuthenticator activates authenticator with SMS code and two-factor code,
+// generated from new shared secret. Steam can ask for codes of several next time windows.
+//
+// Secrets must be stored before finalization, otherwise account can be lost.
+// Revocation code is used to remove authenticator.
+//
+// ITwoFactorService methods require OAuth access token of mobile session.
+package twofactor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/auth"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultBaseURL is Steam WebAPI url.
+	DefaultBaseURL = "https://api.steampowered.com"
+
+	// authenticatorTypeMobile is authenticator_type of mobile app.
+	authenticatorTypeMobile = 1
+
+	// steamGuardSchemeEmail is steamguard_scheme, which is enabled after authenticator removal.
+	steamGuardSchemeEmail = 1
+
+	// maxFinalizeAttempts limits count of codes sent, while Steam wants more.
+	maxFinalizeAttempts = 30
+)
+
+// Client used to call ITwoFactorService WebAPI methods.
+type Client struct {
+	httpCl      *http.Client
+	baseURL     string
+	accessToken string
+	steamID     uint64
+	gen         *auth.TOTPGenerator
+}
+
+// NewClient initialize new instance of Client.
+// baseURL is WebAPI url, DefaultBaseURL is used if it's empty.
+// gen is used to get Steam time and generate codes for finalization.
+// http.DefaultClient is used, if httpCl is nil.
+func NewClient(
+	httpCl *http.Client,
+	baseURL string,
+	accessToken string,
+	steamID uint64,
+	gen *auth.TOTPGenerator,
+) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if httpCl == nil {
+		httpCl = http.DefaultClient
+	}
+
+	return &Client{
+		httpCl:      httpCl,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		accessToken: accessToken,
+		steamID:     steamID,
+		gen:         gen,
+	}
+}
+
+// AddAuthenticator start authenticator enrollment.
+// Returned secrets aren't active until FinalizeAddAuthenticator succeeded.
+func (c *Client) AddAuthenticator(ctx context.Context) (*Authenticator, error) {
+	deviceID := auth.DeviceID(c.steamID)
+
+	params := url.Values{
+		"steamid":            {strconv.FormatUint(c.steamID, 10)},
+		"authenticator_time": {strconv.FormatInt(c.gen.Now().Unix(), 10)},
+		"authenticator_type": {strconv.Itoa(authenticatorTypeMobile)},
+		"device_identifier":  {deviceID},
+		"sms_phone_id":       {"1"},
+	}
+
+	type Response struct {
+		Inner struct {
+			SharedSecret   string      `json:"shared_secret"`
+			SerialNumber   string      `json:"serial_number"`
+			RevocationCode string      `json:"revocation_code"`
+			URI            string      `json:"uri"`
+			ServerTime     json.Number `json:"server_time"`
+			AccountName    string      `json:"account_name"`
+			TokenGID       string      `json:"token_gid"`
+			IdentitySecret string      `json:"identity_secret"`
+			Secret1        string      `json:"secret_1"`
+			Status         int32       `json:"status"`
+		} `json:"response"`
+	}
+
+	var response Response
+
+	err := c.call(ctx, "AddAuthenticator", params, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	result := steamprotocol.EResult(response.Inner.Status)
+	if result != steamprotocol.EResult_OK {
+		return nil, fmt.Errorf("add authenticator failed with result %s", result.String())
+	}
+
+	// Server time is optional, so error is ignored
+	serverTime, _ := response.Inner.ServerTime.Int64()
+
+	return &Authenticator{
+		SharedSecret:   response.Inner.SharedSecret,
+		SerialNumber:   response.Inner.SerialNumber,
+		RevocationCode: response.Inner.RevocationCode,
+		URI:            response.Inner.URI,
+		ServerTime:     serverTime,
+		AccountName:    response.Inner.AccountName,
+		TokenGID:       response.Inner.TokenGID,
+		IdentitySecret: response.Inner.IdentitySecret,
+		Secret1:        response.Inner.Secret1,
+		Status:         response.Inner.Status,
+		DeviceID:       deviceID,
+	}, nil
+}
+
+// FinalizeAddAuthenticator activate authenticator with SMS code.
+// FullyEnrolled of authenticator is set on success.
+func (c *Client) FinalizeAddAuthenticator(ctx context.Context, a *Authenticator, smsCode string) error {
+	type Response struct {
+		Inner struct {
+			Status   int32 `json:"status"`
+			WantMore bool  `json:"want_more"`
+			Success  bool  `json:"success"`
+		} `json:"response"`
+	}
+
+	// Steam can ask codes of next time windows to make sure secret is stored correctly
+	for shift := 0; shift < maxFinalizeAttempts; shift++ {
+		now := c.gen.Now().Add(time.Duration(shift) * auth.TwoFactorPeriod).Unix()
+
+		code, err := c.gen.GenerateTwoFactorCode(a.SharedSecret, now)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate two factor code")
+		}
+
+		params := url.Values{
+			"steamid":            {strconv.FormatUint(c.steamID, 10)},
+			"authenticator_code": {code},
+			"authenticator_time": {strconv.FormatInt(now, 10)},
+			"activation_code":    {smsCode},
+		}
+
+		var response Response
+
+		err = c.call(ctx, "FinalizeAddAuthenticator", params, &response)
+		if err != nil {
+			return err
+		}
+
+		if !response.Inner.Success {
+			result := steamprotocol.EResult(response.Inner.Status)
+
+			return fmt.Errorf("finalize authenticator failed with result %s", result.String())
+		}
+
+		if !response.Inner.WantMore {
+			a.FullyEnrolled = true
+
+			return nil
+		}
+	}
+
+	return errors.New("finalize authenticator wants too many codes")
+}
+
+// RemoveAuthenticator remove authenticator with revocation code.
+// Steam Guard email codes are enabled after removal.
+func (c *Client) RemoveAuthenticator(ctx context.Context, revocationCode string) error {
+	params := url.Values{
+		"steamid":           {strconv.FormatUint(c.steamID, 10)},
+		"revocation_code":   {revocationCode},
+		"steamguard_scheme": {strconv.Itoa(steamGuardSchemeEmail)},
+	}
+
+	type Response struct {
+		Inner struct {
+			Success                     bool  `json:"success"`
+			RevocationAttemptsRemaining int32 `json:"revocation_attempts_remaining"`
+		} `json:"response"`
+	}
+
+	var response Response
+
+	err := c.call(ctx, "RemoveAuthenticator", params, &response)
+	if err != nil {
+		return err
+	}
+
+	if !response.Inner.Success {
+		return fmt.Errorf(
+			"remove authenticator failed, %d revocation attempts remaining",
+			response.Inner.RevocationAttemptsRemaining,
+		)
+	}
+
+	return nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params url.Values, response interface{}) error {
+	params.Set("access_token", c.accessToken)
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		c.baseURL+"/ITwoFactorService/"+method+"/v1/",
+		strings.NewReader(params.Encode()),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpCl.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "failed to call %s", method)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(http.StatusText(resp.StatusCode))
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return errors.Wrapf(err, "failed to decode %s response", method)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package ucm
+
+import "time"
+
+// FileSubscribedEvent is fired when CMsgClientUCMPublishedFileSubscribed is received.
+type FileSubscribedEvent struct {
+	File SubscribedFile
+}
+
+// FileUnsubscribedEvent is fired when CMsgClientUCMPublishedFileUnsubscribed is received.
+type FileUnsubscribedEvent struct {
+	FileID uint64
+	AppID  uint32
+}
+
+// FileDeletedEvent is fired when CMsgClientUCMPublishedFileDeleted is received.
+type FileDeletedEvent struct {
+	FileID uint64
+	AppID  uint32
+}
+
+// FileUpdatedEvent is fired when CMsgClientUCMPublishedFileUpdated is received.
+// Content of subscribed file must be downloaded again, when HContent is changed.
+type FileUpdatedEvent struct {
+	FileID         uint64
+	AppID          uint32
+	TimeUpdated    time.Time
+	HContent       uint64
+	FileSize       uint32
+	IsDepotContent bool
+}
+
+// ScreenshotsChangedEvent is fired when CMsgClientScreenshotsChanged is received.
+type ScreenshotsChangedEvent struct{}
@@ -0,0 +1,289 @@
+// Package ucm used to manage screenshots and workshop subscriptions with
+// legacy UCM (User Created Media) messages:
+// ClientUCMAddScreenshot (client->server): Register screenshot, which is uploaded to Steam Cloud.
+// ClientUCMDeleteScreenshot (client->server): Delete screenshot.
+// ClientUCMEnumerateUserSubscribedFilesWithUpdates (client->server): Request subscribed files page.
+// ClientUCMPublishedFileSubscribed, ClientUCMPublishedFileUnsubscribed,
+// ClientUCMPublishedFileDeleted, UCMPublishedFileUpdated (server->client):
+// Subscribed files changes, which are sent right after they happen.
+//
+// Files of screenshots are stored in Steam Cloud of Screenshots app,
+// so they are written with cloud module before screenshot is registered.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package ucm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/cloud"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ScreenshotsAppID is an app, which Steam Cloud stores screenshots.
+const ScreenshotsAppID = 760
+
+// Screenshot describes screenshot of game.
+type Screenshot struct {
+	AppID     uint32
+	Caption   string
+	Width     uint32
+	Height    uint32
+	Created   time.Time
+	Privacy   steamprotocol.EUCMFilePrivacyState
+	Spoiler   bool
+	Image     []byte
+	Thumbnail []byte
+	// TaggedSteamIDs are users, which are shown on screenshot.
+	TaggedSteamIDs []uint64
+}
+
+// SubscribedFile is a workshop file, which user is subscribed to.
+type SubscribedFile struct {
+	FileID         uint64
+	AppID          uint32
+	HContent       uint64
+	FileSize       uint32
+	TimeSubscribed time.Time
+	TimeUpdated    time.Time
+	IsDepotContent bool
+}
+
+// Module used to manage screenshots and subscribed files.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	cloud        *cloud.Module
+}
+
+// NewModule initialize new instance of ucm Module.
+// cloudModule is used to upload files of screenshots.
+func NewModule(cl *steamprotocol.Client, eventManager *steamprotocol.EventManager, cloudModule *cloud.Module) *Module {
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		cloud:        cloudModule,
+	}
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnPacket(m.handlePacket)
+}
+
+func (m *Module) handlePacket(p *steamprotocol.Packet) error {
+	switch p.Type {
+	case steamprotocol.EMsg_ClientUCMPublishedFileSubscribed:
+		return m.handleFileSubscribed(p)
+	case steamprotocol.EMsg_ClientUCMPublishedFileUnsubscribed:
+		return m.handleFileUnsubscribed(p)
+	case steamprotocol.EMsg_ClientUCMPublishedFileDeleted:
+		return m.handleFileDeleted(p)
+	case steamprotocol.EMsg_UCMPublishedFileUpdated:
+		return m.handleFileUpdated(p)
+	case steamprotocol.EMsg_ClientScreenshotsChanged:
+		return m.eventManager.FireEvent(ScreenshotsChangedEvent{})
+	}
+
+	return nil
+}
+
+// UploadScreenshot write screenshot files to Steam Cloud and register it.
+// Id of new screenshot is returned.
+func (m *Module) UploadScreenshot(ctx context.Context, s Screenshot) (uint64, error) {
+	created := s.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	name := fmt.Sprintf("%d/screenshots/%s_1.jpg", s.AppID, created.Format("20060102150405"))
+	thumbName := fmt.Sprintf("%d/screenshots/thumbnails/%s_1.jpg", s.AppID, created.Format("20060102150405"))
+
+	err := m.cloud.WriteFile(ctx, ScreenshotsAppID, name, s.Image)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload screenshot image")
+	}
+
+	err = m.cloud.WriteFile(ctx, ScreenshotsAppID, thumbName, s.Thumbnail)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload screenshot thumbnail")
+	}
+
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientUCMAddScreenshot, &protobuf.CMsgClientUCMAddScreenshot{
+		Appid:          proto.Uint32(s.AppID),
+		Filename:       proto.String(name),
+		Thumbname:      proto.String(thumbName),
+		Rtime32Created: proto.Uint32(uint32(created.Unix())),
+		Width:          proto.Uint32(s.Width),
+		Height:         proto.Uint32(s.Height),
+		Permissions:    proto.Uint32(uint32(s.Privacy)),
+		Caption:        proto.String(s.Caption),
+		TaggedSteamid:  s.TaggedSteamIDs,
+		SpoilerTag:     proto.Bool(s.Spoiler),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to add screenshot")
+	}
+
+	var msg protobuf.CMsgClientUCMAddScreenshotResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read add screenshot response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return 0, fmt.Errorf("add screenshot failed with result %s", result.String())
+	}
+
+	return msg.GetScreenshotid(), nil
+}
+
+// DeleteScreenshot delete screenshot.
+func (m *Module) DeleteScreenshot(ctx context.Context, screenshotID uint64) error {
+	p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientUCMDeleteScreenshot, &protobuf.CMsgClientUCMDeleteScreenshot{
+		Screenshotid: proto.Uint64(screenshotID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to delete screenshot")
+	}
+
+	var msg protobuf.CMsgClientUCMDeleteScreenshotResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read delete screenshot response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return fmt.Errorf("delete screenshot failed with result %s", result.String())
+	}
+
+	return nil
+}
+
+// SubscribedFiles return files of app, which user is subscribed to.
+// Only files updated after since are returned, zero time means all files.
+func (m *Module) SubscribedFiles(ctx context.Context, appID uint32, since time.Time) ([]SubscribedFile, error) {
+	var startTime uint32
+	if !since.IsZero() {
+		startTime = uint32(since.Unix())
+	}
+
+	var files []SubscribedFile
+
+	for {
+		p, err := m.cl.Call(ctx, steamprotocol.EMsg_ClientUCMEnumerateUserSubscribedFilesWithUpdates, &protobuf.CMsgClientUCMEnumerateUserSubscribedFilesWithUpdates{
+			AppId:      proto.Uint32(appID),
+			StartIndex: proto.Uint32(uint32(len(files))),
+			StartTime:  proto.Uint32(startTime),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to enumerate subscribed files")
+		}
+
+		var msg protobuf.CMsgClientUCMEnumerateUserSubscribedFilesWithUpdatesResponse
+
+		_, err = messages.ReadProto(p.Data, &msg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read subscribed files")
+		}
+
+		result := steamprotocol.EResult(msg.GetEresult())
+		if result != steamprotocol.EResult_OK {
+			return nil, fmt.Errorf("subscribed files enumeration failed with result %s", result.String())
+		}
+
+		for _, f := range msg.GetSubscribedFiles() {
+			files = append(files, SubscribedFile{
+				FileID:         f.GetPublishedFileId(),
+				AppID:          f.GetAppid(),
+				HContent:       f.GetFileHcontent(),
+				FileSize:       f.GetFileSize(),
+				TimeSubscribed: steamprotocol.UnixTime(f.GetRtime32Subscribed()),
+				TimeUpdated:    steamprotocol.UnixTime(f.GetRtime32LastUpdated()),
+				IsDepotContent: f.GetIsDepotContent(),
+			})
+		}
+
+		if len(msg.GetSubscribedFiles()) == 0 || uint32(len(files)) >= msg.GetTotalResults() {
+			return files, nil
+		}
+	}
+}
+
+func (m *Module) handleFileSubscribed(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientUCMPublishedFileSubscribed
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read published file subscribed")
+	}
+
+	return m.eventManager.FireEvent(FileSubscribedEvent{
+		File: SubscribedFile{
+			FileID:         msg.GetPublishedFileId(),
+			AppID:          msg.GetAppId(),
+			HContent:       msg.GetFileHcontent(),
+			FileSize:       msg.GetFileSize(),
+			TimeSubscribed: steamprotocol.UnixTime(msg.GetRtimeSubscribed()),
+			TimeUpdated:    steamprotocol.UnixTime(msg.GetRtimeUpdated()),
+			IsDepotContent: msg.GetIsDepotContent(),
+		},
+	})
+}
+
+func (m *Module) handleFileUnsubscribed(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientUCMPublishedFileUnsubscribed
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read published file unsubscribed")
+	}
+
+	return m.eventManager.FireEvent(FileUnsubscribedEvent{
+		FileID: msg.GetPublishedFileId(),
+		AppID:  msg.GetAppId(),
+	})
+}
+
+func (m *Module) handleFileDeleted(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientUCMPublishedFileDeleted
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read published file deleted")
+	}
+
+	return m.eventManager.FireEvent(FileDeletedEvent{
+		FileID: msg.GetPublishedFileId(),
+		AppID:  msg.GetAppId(),
+	})
+}
+
+func (m *Module) handleFileUpdated(p *steamprotocol.Packet) error {
+	var msg protobuf.CMsgClientUCMPublishedFileUpdated
+
+	_, err := messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to read published file updated")
+	}
+
+	return m.eventManager.FireEvent(FileUpdatedEvent{
+		FileID:         msg.GetPublishedFileId(),
+		AppID:          msg.GetAppId(),
+		TimeUpdated:    steamprotocol.UnixTime(msg.GetTimeUpdated()),
+		HContent:       msg.GetHcontent(),
+		FileSize:       msg.GetFileSize(),
+		IsDepotContent: msg.GetIsDepotContent(),
+	})
+}
@@ -0,0 +1,338 @@
+// Package websession used to get Steam web session cookies for logged on account.
+//
+// Web session is authenticated with nonce, received from CM:
+// 1. Nonce is taken from ClientLogOnResponse, or requested with ClientRequestWebAPIAuthenticateUserNonce.
+// 2. Client generates 32 bytes session key and encrypts it with the universe public RSA key.
+// 3. Nonce is AES encrypted with session key.
+// 4. Encrypted session key and nonce are posted to ISteamUserAuth/AuthenticateUser.
+// 5. WebAPI returns tokens for steamLogin and steamLoginSecure cookies.
+//
+// Nonce can be used only once, so new one is requested for every refresh.
+package websession
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/auth"
+	"github.com/furdarius/steamprotocol/crypto"
+	"github.com/furdarius/steamprotocol/messages"
+	"github.com/furdarius/steamprotocol/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultBaseURL is Steam WebAPI url.
+	DefaultBaseURL = "https://api.steampowered.com"
+
+	// DefaultTTL is time after which web session is refreshed.
+	DefaultTTL = 12 * time.Hour
+)
+
+// DefaultDomains are Steam sites, where session cookies are set.
+var DefaultDomains = []string{
+	"steamcommunity.com",
+	"store.steampowered.com",
+	"help.steampowered.com",
+}
+
+// Session is authenticated Steam web session.
+type Session struct {
+	SteamID     uint64
+	SessionID   string
+	Token       string
+	TokenSecure string
+	Jar         http.CookieJar
+	Expires     time.Time
+}
+
+// Module used to authenticate web sessions.
+type Module struct {
+	eventManager *steamprotocol.EventManager
+	cl           *steamprotocol.Client
+	httpCl       *http.Client
+	baseURL      string
+	domains      []string
+	ttl          time.Duration
+	mu           sync.Mutex
+	nonce        string
+	session      *Session
+}
+
+// NewModule initialize new instance of websession Module.
+// baseURL is WebAPI url, DefaultBaseURL is used if it's empty.
+// http.DefaultClient is used, if httpCl is nil.
+func NewModule(
+	cl *steamprotocol.Client,
+	eventManager *steamprotocol.EventManager,
+	httpCl *http.Client,
+	baseURL string,
+) *Module {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if httpCl == nil {
+		httpCl = http.DefaultClient
+	}
+
+	return &Module{
+		cl:           cl,
+		eventManager: eventManager,
+		httpCl:       httpCl,
+		baseURL:      baseURL,
+		domains:      DefaultDomains,
+		ttl:          DefaultTTL,
+	}
+}
+
+// SetTTL change time after which web session is refreshed.
+func (m *Module) SetTTL(ttl time.Duration) {
+	m.mu.Lock()
+	m.ttl = ttl
+	m.mu.Unlock()
+}
+
+// SetDomains change Steam sites, where session cookies are set.
+func (m *Module) SetDomains(domains []string) {
+	m.mu.Lock()
+	m.domains = domains
+	m.mu.Unlock()
+}
+
+// Subscribe used to start listen event and packets from eventManager.
+func (m *Module) Subscribe() {
+	m.eventManager.OnEvent(m.handleEvent)
+}
+
+func (m *Module) handleEvent(e interface{}) error {
+	switch event := e.(type) {
+	case auth.SuccessfullyAuthenticatedEvent:
+		m.mu.Lock()
+		m.nonce = event.WebAPINonce
+		m.session = nil
+		m.mu.Unlock()
+	case auth.LoggedOffEvent:
+		m.mu.Lock()
+		m.nonce = ""
+		m.session = nil
+		m.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Session return current web session, and authenticate new one, if it's expired.
+// It blocks until nonce is received, so it mustn't be called from event and packet handlers.
+func (m *Module) Session(ctx context.Context) (*Session, error) {
+	m.mu.Lock()
+	session := m.session
+	m.mu.Unlock()
+
+	if session != nil && time.Now().Before(session.Expires) {
+		return session, nil
+	}
+
+	return m.Refresh(ctx)
+}
+
+// Refresh authenticate new web session, even if current one isn't expired.
+// It's useful, when Steam sites reject current session cookies.
+func (m *Module) Refresh(ctx context.Context) (*Session, error) {
+	m.mu.Lock()
+	nonce := m.nonce
+	m.nonce = ""
+	ttl := m.ttl
+	domains := m.domains
+	m.mu.Unlock()
+
+	if nonce == "" {
+		var err error
+
+		nonce, err = m.requestNonce(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to request nonce")
+		}
+	}
+
+	session, err := AuthenticateUser(ctx, m.httpCl, m.baseURL, m.cl.Session().SteamID, nonce, domains)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Expires = time.Now().Add(ttl)
+
+	m.mu.Lock()
+	m.session = session
+	m.mu.Unlock()
+
+	return session, nil
+}
+
+func (m *Module) requestNonce(ctx context.Context) (string, error) {
+	p, err := m.cl.Call(
+		ctx,
+		steamprotocol.EMsg_ClientRequestWebAPIAuthenticateUserNonce,
+		&protobuf.CMsgClientRequestWebAPIAuthenticateUserNonce{},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var msg protobuf.CMsgClientRequestWebAPIAuthenticateUserNonceResponse
+
+	_, err = messages.ReadProto(p.Data, &msg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read nonce response")
+	}
+
+	result := steamprotocol.EResult(msg.GetEresult())
+	if result != steamprotocol.EResult_OK {
+		return "", fmt.Errorf("nonce request failed with result %s", result.String())
+	}
+
+	return msg.GetWebapiAuthenticateUserNonce(), nil
+}
+
+// AuthenticateUser post encrypted nonce to ISteamUserAuth/AuthenticateUser
+// and return session with cookies set for domains.
+// http.DefaultClient is used, if httpCl is nil.
+func AuthenticateUser(
+	ctx context.Context,
+	httpCl *http.Client,
+	baseURL string,
+	steamID uint64,
+	nonce string,
+	domains []string,
+) (*Session, error) {
+	if httpCl == nil {
+		httpCl = http.DefaultClient
+	}
+
+	pub, err := crypto.PublicKey(steamprotocol.EUniverse_Public)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get public key")
+	}
+
+	sessionKey := make([]byte, 32)
+
+	_, err = rand.Read(sessionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate session key")
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, sessionKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt session key")
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher from session key")
+	}
+
+	encryptedNonce, err := crypto.NewAes(block).Encrypt([]byte(nonce))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt nonce")
+	}
+
+	form := url.Values{
+		"steamid":            {strconv.FormatUint(steamID, 10)},
+		"sessionkey":         {string(encryptedKey)},
+		"encrypted_loginkey": {string(encryptedNonce)},
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		strings.TrimRight(baseURL, "/")+"/ISteamUserAuth/AuthenticateUser/v1/",
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpCl.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to authenticate user")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(http.StatusText(resp.StatusCode))
+	}
+
+	type Response struct {
+		Inner struct {
+			Token       string `json:"token"`
+			TokenSecure string `json:"tokensecure"`
+		} `json:"authenticateuser"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	if response.Inner.Token == "" || response.Inner.TokenSecure == "" {
+		return nil, errors.New("response doesn't contain tokens")
+	}
+
+	sessionID := make([]byte, 12)
+
+	_, err = rand.Read(sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate session id")
+	}
+
+	session := &Session{
+		SteamID:     steamID,
+		SessionID:   hex.EncodeToString(sessionID),
+		Token:       response.Inner.Token,
+		TokenSecure: response.Inner.TokenSecure,
+	}
+
+	session.Jar, err = session.cookieJar(domains)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cookie jar")
+	}
+
+	return session, nil
+}
+
+func (s *Session) cookieJar(domains []string) (http.CookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	steamID := strconv.FormatUint(s.SteamID, 10)
+
+	cookies := []*http.Cookie{
+		{Name: "sessionid", Value: s.SessionID, Path: "/"},
+		{Name: "steamLogin", Value: steamID + "%7C%7C" + s.Token, Path: "/", HttpOnly: true},
+		{Name: "steamLoginSecure", Value: steamID + "%7C%7C" + s.TokenSecure, Path: "/", HttpOnly: true, Secure: true},
+	}
+
+	for _, domain := range domains {
+		jar.SetCookies(&url.URL{Scheme: "https", Host: domain, Path: "/"}, cookies)
+	}
+
+	return jar, nil
+}
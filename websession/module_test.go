@@ -0,0 +1,93 @@
+package websession
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const testSteamID = 76561197960287930
+
+func newAuthServer(t *testing.T, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/ISteamUserAuth/AuthenticateUser/v1/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+
+		if r.PostForm.Get("steamid") != "76561197960287930" {
+			t.Errorf("unexpected steamid %q", r.PostForm.Get("steamid"))
+		}
+
+		// Session key is RSA encrypted with 1024 bits key.
+		if len(r.PostForm.Get("sessionkey")) != 128 {
+			t.Errorf("unexpected session key size %d", len(r.PostForm.Get("sessionkey")))
+		}
+
+		if r.PostForm.Get("encrypted_loginkey") == "" {
+			t.Error("encrypted nonce is empty")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestAuthenticateUser(t *testing.T) {
+	srv := newAuthServer(t, `{"authenticateuser":{"token":"TOKEN","tokensecure":"SECURE"}}`)
+	defer srv.Close()
+
+	session, err := AuthenticateUser(context.Background(), srv.Client(), srv.URL, testSteamID, "nonce", DefaultDomains)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if session.Token != "TOKEN" || session.TokenSecure != "SECURE" {
+		t.Fatalf("unexpected tokens %q, %q", session.Token, session.TokenSecure)
+	}
+
+	cookies := make(map[string]string)
+	for _, c := range session.Jar.Cookies(&url.URL{Scheme: "https", Host: "steamcommunity.com", Path: "/"}) {
+		cookies[c.Name] = c.Value
+	}
+
+	expected := map[string]string{
+		"sessionid":        session.SessionID,
+		"steamLogin":       "76561197960287930%7C%7CTOKEN",
+		"steamLoginSecure": "76561197960287930%7C%7CSECURE",
+	}
+
+	for name, value := range expected {
+		if cookies[name] != value {
+			t.Errorf("cookie %s is %q, expected %q", name, cookies[name], value)
+		}
+	}
+}
+
+func TestAuthenticateUserEmptyTokens(t *testing.T) {
+	srv := newAuthServer(t, `{"authenticateuser":{}}`)
+	defer srv.Close()
+
+	_, err := AuthenticateUser(context.Background(), srv.Client(), srv.URL, testSteamID, "nonce", DefaultDomains)
+	if err == nil {
+		t.Fatal("expected error for response without tokens")
+	}
+}
+
+func TestAuthenticateUserStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := AuthenticateUser(context.Background(), srv.Client(), srv.URL, testSteamID, "nonce", DefaultDomains)
+	if err == nil {
+		t.Fatal("expected error for forbidden response")
+	}
+}
@@ -0,0 +1,211 @@
+// Package workshop used to query, subscribe and publish Steam Workshop files
+// with PublishedFile unified service.
+//
+// Contents of published files are stored in Steam Cloud, so they must be
+// written with cloud module before Publish or Update is called.
+//
+// Methods of Module block until response is received,
+// so they mustn't be called from event and packet handlers.
+package workshop
+
+import (
+	"context"
+	"time"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+)
+
+// ListType is a list of user, which file is added to.
+type ListType uint32
+
+// Lists of published files.
+const (
+	ListSubscribed ListType = 1
+	ListFavorited  ListType = 2
+)
+
+// File is a published workshop file.
+type File struct {
+	ID            uint64
+	Result        steamprotocol.EResult
+	Creator       uint64
+	CreatorAppID  uint32
+	ConsumerAppID uint32
+	Type          steamprotocol.EWorkshopFileType
+	Visibility    steamprotocol.EPublishedFileVisibility
+	Title         string
+	Description   string
+	FileName      string
+	FileSize      uint64
+	FileURL       string
+	PreviewURL    string
+	Tags          []string
+	TimeCreated   time.Time
+	TimeUpdated   time.Time
+	Subscriptions uint32
+	Favorited     uint32
+	Views         uint32
+	Banned        bool
+}
+
+// PublishDetails describes new workshop file.
+// FileName and PreviewFileName are names of files in Steam Cloud of app.
+type PublishDetails struct {
+	AppID           uint32
+	ConsumerAppID   uint32
+	FileName        string
+	PreviewFileName string
+	Title           string
+	Description     string
+	Type            steamprotocol.EWorkshopFileType
+	Visibility      steamprotocol.EPublishedFileVisibility
+	Tags            []string
+}
+
+// UpdateDetails describes changes of workshop file.
+// Nil fields are left unchanged.
+type UpdateDetails struct {
+	Title           *string
+	Description     *string
+	Visibility      *steamprotocol.EPublishedFileVisibility
+	Tags            []string
+	FileName        *string
+	PreviewFileName *string
+}
+
+// Module used to work with Steam Workshop.
+type Module struct {
+	cl *steamprotocol.Client
+}
+
+// NewModule initialize new instance of workshop Module.
+func NewModule(cl *steamprotocol.Client) *Module {
+	return &Module{
+		cl: cl,
+	}
+}
+
+// GetDetails return details of published files.
+// Result of file is set to failed EResult, if file can't be found.
+func (m *Module) GetDetails(ctx context.Context, fileIDs ...uint64) ([]*File, error) {
+	var resp unified.CPublishedFile_GetDetails_Response
+
+	err := m.cl.CallService(ctx, "PublishedFile.GetDetails#1", &unified.CPublishedFile_GetDetails_Request{
+		Publishedfileids: fileIDs,
+		Includetags:      proto.Bool(true),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*File, 0, len(resp.GetPublishedfiledetails()))
+	for _, d := range resp.GetPublishedfiledetails() {
+		files = append(files, newFile(d))
+	}
+
+	return files, nil
+}
+
+// Subscribe add file to list of user.
+func (m *Module) Subscribe(ctx context.Context, appID uint32, fileID uint64, list ListType) error {
+	var resp unified.CPublishedFile_Subscribe_Response
+
+	return m.cl.CallService(ctx, "PublishedFile.Subscribe#1", &unified.CPublishedFile_Subscribe_Request{
+		Publishedfileid: proto.Uint64(fileID),
+		ListType:        proto.Uint32(uint32(list)),
+		Appid:           proto.Int32(int32(appID)),
+		NotifyClient:    proto.Bool(true),
+	}, &resp)
+}
+
+// Unsubscribe remove file from list of user.
+func (m *Module) Unsubscribe(ctx context.Context, appID uint32, fileID uint64, list ListType) error {
+	var resp unified.CPublishedFile_Unsubscribe_Response
+
+	return m.cl.CallService(ctx, "PublishedFile.Unsubscribe#1", &unified.CPublishedFile_Unsubscribe_Request{
+		Publishedfileid: proto.Uint64(fileID),
+		ListType:        proto.Uint32(uint32(list)),
+		Appid:           proto.Int32(int32(appID)),
+		NotifyClient:    proto.Bool(true),
+	}, &resp)
+}
+
+// Publish create new workshop file and return its id.
+func (m *Module) Publish(ctx context.Context, d PublishDetails) (uint64, error) {
+	consumerAppID := d.ConsumerAppID
+	if consumerAppID == 0 {
+		consumerAppID = d.AppID
+	}
+
+	var resp unified.CPublishedFile_Publish_Response
+
+	err := m.cl.CallService(ctx, "PublishedFile.Publish#1", &unified.CPublishedFile_Publish_Request{
+		Appid:                proto.Uint32(d.AppID),
+		ConsumerAppid:        proto.Uint32(consumerAppID),
+		Cloudfilename:        proto.String(d.FileName),
+		PreviewCloudfilename: proto.String(d.PreviewFileName),
+		Title:                proto.String(d.Title),
+		FileDescription:      proto.String(d.Description),
+		FileType:             proto.Uint32(uint32(d.Type)),
+		Visibility:           proto.Uint32(uint32(d.Visibility)),
+		Tags:                 d.Tags,
+	}, &resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.GetPublishedfileid(), nil
+}
+
+// Update change details of published file.
+func (m *Module) Update(ctx context.Context, appID uint32, fileID uint64, d UpdateDetails) error {
+	req := &unified.CPublishedFile_Update_Request{
+		Appid:           proto.Uint32(appID),
+		Publishedfileid: proto.Uint64(fileID),
+		Title:           d.Title,
+		FileDescription: d.Description,
+		Tags:            d.Tags,
+		Filename:        d.FileName,
+		PreviewFilename: d.PreviewFileName,
+	}
+
+	if d.Visibility != nil {
+		req.Visibility = proto.Uint32(uint32(*d.Visibility))
+	}
+
+	var resp unified.CPublishedFile_Update_Response
+
+	return m.cl.CallService(ctx, "PublishedFile.Update#1", req, &resp)
+}
+
+func newFile(d *unified.PublishedFileDetails) *File {
+	f := &File{
+		ID:            d.GetPublishedfileid(),
+		Result:        steamprotocol.EResult(d.GetResult()),
+		Creator:       d.GetCreator(),
+		CreatorAppID:  d.GetCreatorAppid(),
+		ConsumerAppID: d.GetConsumerAppid(),
+		Type:          steamprotocol.EWorkshopFileType(d.GetFileType()),
+		Visibility:    steamprotocol.EPublishedFileVisibility(d.GetVisibility()),
+		Title:         d.GetTitle(),
+		Description:   d.GetFileDescription(),
+		FileName:      d.GetFilename(),
+		FileSize:      d.GetFileSize(),
+		FileURL:       d.GetFileUrl(),
+		PreviewURL:    d.GetPreviewUrl(),
+		TimeCreated:   steamprotocol.UnixTime(d.GetTimeCreated()),
+		TimeUpdated:   steamprotocol.UnixTime(d.GetTimeUpdated()),
+		Subscriptions: d.GetSubscriptions(),
+		Favorited:     d.GetFavorited(),
+		Views:         d.GetViews(),
+		Banned:        d.GetBanned(),
+	}
+
+	for _, t := range d.GetTags() {
+		f.Tags = append(f.Tags, t.GetTag())
+	}
+
+	return f
+}
@@ -0,0 +1,237 @@
+package workshop
+
+import (
+	"context"
+
+	"github.com/furdarius/steamprotocol"
+	"github.com/furdarius/steamprotocol/protobuf/unified"
+	"github.com/golang/protobuf/proto"
+)
+
+// DefaultPageSize is a count of files requested by one page of Iterator.
+const DefaultPageSize = 100
+
+// QueryType is a sort order of queried files.
+type QueryType uint32
+
+// Sort orders of PublishedFile.QueryFiles.
+const (
+	QueryRankedByVote                                  QueryType = 0
+	QueryRankedByPublicationDate                       QueryType = 1
+	QueryAcceptedForGameRankedByAcceptanceDate         QueryType = 2
+	QueryRankedByTrend                                 QueryType = 3
+	QueryFavoritedByFriendsRankedByPublicationDate     QueryType = 4
+	QueryCreatedByFriendsRankedByPublicationDate       QueryType = 5
+	QueryRankedByNumTimesReported                      QueryType = 6
+	QueryCreatedByFollowedUsersRankedByPublicationDate QueryType = 7
+	QueryNotYetRated                                   QueryType = 8
+	QueryRankedByTotalUniqueSubscriptions              QueryType = 9
+	QueryRankedByTotalVotesAsc                         QueryType = 10
+	QueryRankedByVotesUp                               QueryType = 11
+	QueryRankedByTextSearch                            QueryType = 12
+	QueryRankedByPlaytimeTrend                         QueryType = 13
+	QueryRankedByTotalPlaytime                         QueryType = 14
+)
+
+// matchingFileTypes maps EWorkshopFileType to EPublishedFileInfoMatchingFileType,
+// which is used as file type filter of PublishedFile.QueryFiles.
+var matchingFileTypes = map[steamprotocol.EWorkshopFileType]uint32{
+	steamprotocol.EWorkshopFileType_Community:              0,
+	steamprotocol.EWorkshopFileType_Collection:             1,
+	steamprotocol.EWorkshopFileType_Art:                    2,
+	steamprotocol.EWorkshopFileType_Video:                  3,
+	steamprotocol.EWorkshopFileType_Screenshot:             4,
+	steamprotocol.EWorkshopFileType_Game:                   6,
+	steamprotocol.EWorkshopFileType_Software:               7,
+	steamprotocol.EWorkshopFileType_Concept:                8,
+	steamprotocol.EWorkshopFileType_WebGuide:               11,
+	steamprotocol.EWorkshopFileType_IntegratedGuide:        12,
+	steamprotocol.EWorkshopFileType_Merch:                  14,
+	steamprotocol.EWorkshopFileType_ControllerBinding:      15,
+	steamprotocol.EWorkshopFileType_SteamworksAccessInvite: 16,
+	steamprotocol.EWorkshopFileType_Microtransaction:       17,
+	steamprotocol.EWorkshopFileType_GameManagedItem:        20,
+}
+
+// Query is a filter of PublishedFile.QueryFiles.
+// Zero values of optional fields mean no filtering.
+type Query struct {
+	Type          QueryType
+	AppID         uint32
+	CreatorAppID  uint32
+	SearchText    string
+	RequiredTags  []string
+	ExcludedTags  []string
+	MatchAnyTag   bool
+	FileType      *steamprotocol.EWorkshopFileType
+	Visibility    *steamprotocol.EPublishedFileVisibility
+	Days          uint32
+	PageSize      uint32
+	ReturnTags    bool
+	ReturnPreview bool
+}
+
+// Iterator used to walk through files page by page.
+//
+//	it := m.QueryFiles(query)
+//	for it.Next(ctx) {
+//		file := it.File()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	fetch  func(ctx context.Context, page uint32) ([]*unified.PublishedFileDetails, uint32, error)
+	filter func(*File) bool
+
+	page    uint32
+	total   uint32
+	fetched uint32
+	files   []*File
+	current *File
+	done    bool
+	err     error
+}
+
+// Next advance iterator to the next file, requesting next page when it's needed.
+// False is returned when files are over, or error occurred.
+func (it *Iterator) Next(ctx context.Context) bool {
+	for len(it.files) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		it.page++
+
+		details, total, err := it.fetch(ctx, it.page)
+		if err != nil {
+			it.err = err
+
+			return false
+		}
+
+		it.total = total
+		it.fetched += uint32(len(details))
+
+		if len(details) == 0 || it.fetched >= total {
+			it.done = true
+		}
+
+		for _, d := range details {
+			f := newFile(d)
+
+			if it.filter == nil || it.filter(f) {
+				it.files = append(it.files, f)
+			}
+		}
+	}
+
+	it.current = it.files[0]
+	it.files = it.files[1:]
+
+	return true
+}
+
+// File return current file of iterator.
+func (it *Iterator) File() *File {
+	return it.current
+}
+
+// Total return total count of files reported by Steam.
+// It's available after the first call of Next.
+func (it *Iterator) Total() uint32 {
+	return it.total
+}
+
+// Err return error, which stopped iteration.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// QueryFiles return iterator over files matched by query.
+func (m *Module) QueryFiles(q Query) *Iterator {
+	pageSize := q.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+
+	it := &Iterator{}
+
+	it.fetch = func(ctx context.Context, page uint32) ([]*unified.PublishedFileDetails, uint32, error) {
+		req := &unified.CPublishedFile_QueryFiles_Request{
+			QueryType:      proto.Uint32(uint32(q.Type)),
+			Page:           proto.Uint32(page),
+			Numperpage:     proto.Uint32(pageSize),
+			Appid:          proto.Uint32(q.AppID),
+			CreatorAppid:   proto.Uint32(q.CreatorAppID),
+			Requiredtags:   q.RequiredTags,
+			Excludedtags:   q.ExcludedTags,
+			MatchAllTags:   proto.Bool(!q.MatchAnyTag),
+			ReturnTags:     proto.Bool(q.ReturnTags),
+			ReturnPreviews: proto.Bool(q.ReturnPreview),
+		}
+
+		if len(q.SearchText) > 0 {
+			req.SearchText = proto.String(q.SearchText)
+		}
+
+		if q.Days > 0 {
+			req.Days = proto.Uint32(q.Days)
+		}
+
+		if q.FileType != nil {
+			if t, ok := matchingFileTypes[*q.FileType]; ok {
+				req.Filetype = proto.Uint32(t)
+			}
+		}
+
+		var resp unified.CPublishedFile_QueryFiles_Response
+
+		err := m.cl.CallService(ctx, "PublishedFile.QueryFiles#1", req, &resp)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return resp.GetPublishedfiledetails(), resp.GetTotal(), nil
+	}
+
+	// Matching file types are wider than EWorkshopFileType, and visibility
+	// can't be filtered by Steam, so results are filtered again.
+	it.filter = func(f *File) bool {
+		if q.FileType != nil && f.Type != *q.FileType {
+			return false
+		}
+
+		if q.Visibility != nil && f.Visibility != *q.Visibility {
+			return false
+		}
+
+		return true
+	}
+
+	return it
+}
+
+// UserFiles return iterator over files published by user for app.
+func (m *Module) UserFiles(steamID uint64, appID uint32) *Iterator {
+	it := &Iterator{}
+
+	it.fetch = func(ctx context.Context, page uint32) ([]*unified.PublishedFileDetails, uint32, error) {
+		var resp unified.CPublishedFile_GetUserFiles_Response
+
+		err := m.cl.CallService(ctx, "PublishedFile.GetUserFiles#1", &unified.CPublishedFile_GetUserFiles_Request{
+			Steamid:    proto.Uint64(steamID),
+			Appid:      proto.Uint32(appID),
+			Page:       proto.Uint32(page),
+			Numperpage: proto.Uint32(DefaultPageSize),
+			ReturnTags: proto.Bool(true),
+		}, &resp)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return resp.GetPublishedfiledetails(), resp.GetTotal(), nil
+	}
+
+	return it
+}